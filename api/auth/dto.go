@@ -1,5 +1,7 @@
 package auth
 
+import "github.com/google/uuid"
+
 type registerBody struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
@@ -17,3 +19,22 @@ type loginTotpBody struct {
 	State string `json:"state" binding:"required"`
 	Code  string `json:"code" binding:"required"`
 }
+
+type unlockAccountBody struct {
+	UserID uuid.UUID `json:"userId" binding:"required"`
+	Token  string    `json:"token" binding:"required"`
+}
+
+// Device authorization requests are form encoded per RFC 8628, json is accepted as well.
+type deviceCodeBody struct {
+	ClientName string `json:"client_name" form:"client_name"`
+}
+
+type deviceTokenBody struct {
+	GrantType  string `json:"grant_type" form:"grant_type" binding:"required"`
+	DeviceCode string `json:"device_code" form:"device_code" binding:"required"`
+}
+
+type approveDeviceBody struct {
+	CarrierIds []uuid.UUID `json:"carrierIds" binding:"required,min=1"`
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -32,10 +33,17 @@ func RegisterRoutes(api *gin.RouterGroup) {
 	authApi.POST("/refresh", refreshToken)
 	authApi.POST("/logout", logout)
 	authApi.POST("/logout/all", logoutAll)
+	authApi.POST("/unlock", unlockAccount)
 
 	authApi.GET("/quicklogin", requestQuickLoginToken)
 	authApi.PUT("/quicklogin", verifyQuickLoginToken)
 	authApi.POST("/quicklogin", completeQuickLogin)
+
+	authApi.POST("/device/code", requestDevicePairing)
+	authApi.POST("/device/token", pollDevicePairing)
+	authApi.GET("/device/:userCode", getDevicePairing)
+	authApi.PUT("/device/:userCode", approveDevicePairing)
+	authApi.DELETE("/device/:userCode", denyDevicePairing)
 }
 
 func register(c *gin.Context) {
@@ -78,9 +86,30 @@ func login(c *gin.Context) {
 		return
 	}
 
-	token, user, err := auth.Login(dto.Email, dto.Password, dto.Otp)
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP(), Email: dto.Email}
+
+	// an email that reached its limit is answered like a locked account without checking the password,
+	// so unknown emails can't be told apart from accounts locked by the same failures
+	if auth.IsEmailExhausted(auth.AttemptLogin, dto.Email) {
+		if auth.CheckAttempts(c, auth.AttemptLogin, auth.AttemptKeys{IP: attemptKeys.IP}) {
+			auth.RecordFailedAttempt(auth.AttemptLogin, attemptKeys)
+			errors.ReturnWithError(c, auth.ErrUserNotFoundOrInvalidCredentials)
+		}
+		return
+	}
+
+	if !auth.CheckAttempts(c, auth.AttemptLogin, attemptKeys) {
+		return
+	}
+
+	token, user, err := auth.Login(dto.Email, dto.Password, dto.Otp, auth.NewSessionMetadata(c, auth.LoginMethodPassword))
+	if user != nil {
+		attemptKeys.UserID = user.ID
+	}
+
 	if err == auth.ErrUserNotFound || err == auth.ErrInvalidCredentials {
 		c.Error(err)
+		auth.RecordFailedAttempt(auth.AttemptLogin, attemptKeys)
 		errors.ReturnWithError(c, auth.ErrUserNotFoundOrInvalidCredentials)
 		return
 	}
@@ -108,6 +137,7 @@ func login(c *gin.Context) {
 		otpState := cache.BeginState("login_otp", map[string]string{
 			"email":    dto.Email,
 			"password": dto.Password,
+			"user":     user.ID.String(),
 		}, time.Minute*3)
 
 		c.JSON(err.HtmlCode(), gin.H{
@@ -121,6 +151,7 @@ func login(c *gin.Context) {
 
 	if err == auth.ErrUserOtpWrong {
 		c.Error(err)
+		auth.RecordFailedAttempt(auth.AttemptLogin, attemptKeys)
 
 		errors.ReturnWithError(c, auth.ErrInvalidCredentials)
 		return
@@ -131,6 +162,8 @@ func login(c *gin.Context) {
 		panic(err)
 	}
 
+	auth.ClearFailedAttempts(auth.AttemptLogin, attemptKeys)
+
 	c.SetCookie("refresh_token", token.RefreshToken, 60*60*24*30, "/", "", false, true)
 	c.JSON(200, token)
 }
@@ -149,7 +182,20 @@ func loginTotp(c *gin.Context) {
 		return
 	}
 
-	token, user, err := auth.Login(payload["email"], payload["password"], &dto.Code)
+	// the state is only handed out after the password matched, so the account is known before the code is checked
+	userID, parseErr := uuid.Parse(payload["user"])
+	if parseErr != nil {
+		errors.ReturnWithError(c, auth.ErrInvalidState)
+		return
+	}
+
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP(), Email: payload["email"], UserID: userID}
+	if !auth.CheckAttempts(c, auth.AttemptLoginTotp, attemptKeys) {
+		return
+	}
+
+	token, user, err := auth.Login(payload["email"], payload["password"], &dto.Code, auth.NewSessionMetadata(c, auth.LoginMethodPassword))
+
 	if err == auth.ErrUserNotFound || err == auth.ErrInvalidCredentials {
 		c.Error(err)
 		errors.ReturnWithError(c, auth.ErrUserNotFoundOrInvalidCredentials)
@@ -176,9 +222,11 @@ func loginTotp(c *gin.Context) {
 
 	if err == auth.ErrUserOtpWrong {
 		c.Error(err)
+		auth.RecordFailedAttempt(auth.AttemptLoginTotp, attemptKeys)
 		otpState := cache.BeginState("login_otp", map[string]string{
 			"email":    payload["email"],
 			"password": payload["password"],
+			"user":     payload["user"],
 		}, time.Minute*3)
 
 		c.JSON(err.HtmlCode(), gin.H{
@@ -195,6 +243,9 @@ func loginTotp(c *gin.Context) {
 		panic(err)
 	}
 
+	auth.ClearFailedAttempts(auth.AttemptLoginTotp, attemptKeys)
+	auth.ClearFailedAttempts(auth.AttemptLogin, attemptKeys)
+
 	c.SetCookie("refresh_token", token.RefreshToken, 60*60*24*30, "/", "", false, true)
 	c.JSON(200, token)
 }
@@ -207,8 +258,8 @@ func refreshToken(c *gin.Context) {
 		return
 	}
 
-	token, err := auth.Refresh(refreshToken)
-	if err == auth.ErrUsedRefreshToken {
+	token, err := auth.Refresh(refreshToken, auth.NewSessionMetadata(c, ""))
+	if err == auth.ErrUsedRefreshToken || err == auth.ErrAbsoluteExpReached {
 		c.Error(err)
 		errors.ReturnWithError(c, auth.ErrInvalidToken)
 		return
@@ -231,7 +282,7 @@ func logout(c *gin.Context) {
 		return
 	}
 
-	err = auth.Logout(refreshToken, false)
+	err = auth.Logout(refreshToken, auth.IdentityTokenFromHeader(c), false)
 	if err != nil {
 		c.Error(err)
 		panic(err)
@@ -249,7 +300,7 @@ func logoutAll(c *gin.Context) {
 		return
 	}
 
-	err = auth.Logout(refreshToken, true)
+	err = auth.Logout(refreshToken, auth.IdentityTokenFromHeader(c), true)
 	if err != nil {
 		c.Error(err)
 		panic(err)
@@ -331,7 +382,7 @@ func discordLoginCallback(c *gin.Context) {
 		return
 	}
 
-	token, err := auth.CreateTokenPairForUser(user)
+	token, err := auth.CreateTokenPairForUser(user, auth.NewSessionMetadata(c, auth.LoginMethodDiscord))
 	if err != nil {
 		c.Redirect(http.StatusTemporaryRedirect, getRedirect("success=false"))
 		return
@@ -378,7 +429,7 @@ func endLoginFido2(c *gin.Context) {
 		panic(err)
 	}
 
-	token, err := auth.CreateTokenPairForUser(user)
+	token, err := auth.CreateTokenPairForUser(user, auth.NewSessionMetadata(c, auth.LoginMethodFido2))
 	if err != nil {
 		c.Error(err)
 		panic(err)
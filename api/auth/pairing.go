@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/errors"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /auth/device/code -> starts pairing a connector client, the client shows the user code to the user
+func requestDevicePairing(c *gin.Context) {
+	dto := deviceCodeBody{}
+	if err := c.ShouldBind(&dto); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	authorization, err := auth.RequestDevicePairing(dto.ClientName)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, authorization)
+}
+
+// POST /auth/device/token -> polled by the client until the user confirmed the pairing
+func pollDevicePairing(c *gin.Context) {
+	dto := deviceTokenBody{}
+	if err := c.ShouldBind(&dto); err != nil {
+		errors.ReturnWithOAuthError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.GrantType != auth.DeviceCodeGrantType {
+		errors.ReturnWithOAuthError(c, auth.ErrUnsupportedGrantType)
+		return
+	}
+
+	token, clear, err := auth.PollDevicePairing(dto.DeviceCode)
+	if err != nil {
+		errors.ReturnWithOAuthError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"access_token": clear,
+		"token_type":   "X-RST-Token",
+		"expires_in":   int(time.Until(token.ExpiresAt).Seconds()),
+		"scope":        strings.Join(token.Scopes, " "),
+		"user_id":      token.UserID,
+	})
+}
+
+// GET /auth/device/:userCode -> pending pairing for the confirmation page
+func getDevicePairing(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorize(c); !authorized {
+		return
+	}
+
+	info, err := auth.GetDevicePairing(c.Param("userCode"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, info)
+}
+
+// PUT /auth/device/:userCode -> confirms the pairing for the chosen carriers
+func approveDevicePairing(c *gin.Context) {
+	user, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	dto := approveDeviceBody{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if err := auth.ApproveDevicePairing(c.Param("userCode"), user, dto.CarrierIds); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Device paired"})
+}
+
+// DELETE /auth/device/:userCode -> rejects the pairing, the client stops polling
+func denyDevicePairing(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorize(c); !authorized {
+		return
+	}
+
+	if err := auth.DenyDevicePairing(c.Param("userCode")); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Device pairing denied"})
+}
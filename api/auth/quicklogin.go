@@ -39,9 +39,16 @@ func verifyQuickLoginToken(c *gin.Context) {
 
 	token := verifyDTO.Token
 
+	// the 6 digit codes are easy to guess, confirming someone else's code would log them into this account
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP(), UserID: user.ID}
+	if !auth.CheckAttempts(c, auth.AttemptQuicklogin, attemptKeys) {
+		return
+	}
+
 	err := auth.VerifyQuickLoginToken(token, user)
 	if err != nil {
 		c.Error(err)
+		auth.RecordFailedAttempt(auth.AttemptQuicklogin, attemptKeys)
 		errors.ReturnWithError(c, auth.ErrQuickloginTokenValidationFailed)
 		return
 	}
@@ -64,9 +71,15 @@ func completeQuickLogin(c *gin.Context) {
 	token := dto.Token
 	sessionID := dto.SessionID
 
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP()}
+	if !auth.CheckAttempts(c, auth.AttemptQuicklogin, attemptKeys) {
+		return
+	}
+
 	userId, err := auth.CompleteQuickLogin(token, sessionID)
 	if err != nil {
 		c.Error(err)
+		auth.RecordFailedAttempt(auth.AttemptQuicklogin, attemptKeys)
 		errors.ReturnWithError(c, auth.ErrQuickloginCompletionFailed)
 		return
 	}
@@ -90,7 +103,7 @@ func completeQuickLogin(c *gin.Context) {
 		return
 	}
 
-	jwttoken, err := auth.CreateTokenPairForUser(&user)
+	jwttoken, err := auth.CreateTokenPairForUser(&user, auth.NewSessionMetadata(c, auth.LoginMethodQuicklogin))
 	if err != nil {
 		c.Error(err)
 		errors.ReturnWithError(c, auth.ErrQuickloginCompletionFailed)
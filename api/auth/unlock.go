@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /auth/unlock -> lifts a lockout after too many failed logins with the token of the unlock email
+func unlockAccount(c *gin.Context) {
+	dto := &unlockAccountBody{}
+	if err := c.ShouldBindJSON(dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if err := auth.UnlockAccount(dto.UserID, dto.Token); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"message": "Account unlocked successfully"})
+}
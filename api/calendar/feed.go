@@ -0,0 +1,42 @@
+package calendar
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/calendar"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /calendar/:token/jumps.ics -> scheduled jumps of all carriers of the token owner
+func getUserCalendar(c *gin.Context) {
+	user := auth.AuthenticateCalendarToken(c.Param("token"))
+	if user == nil {
+		errors.ReturnWithError(c, auth.ErrInvalidCalendarToken)
+		return
+	}
+
+	query := db.DB
+	if !user.IsAdmin {
+		query = query.Where("owner_id = ?", user.ID)
+	}
+
+	carriers := []entities.Carrier{}
+	if res := query.Order("name").Find(&carriers); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	feed, err := calendar.BuildCarriersFeed("Ruehrstaat Carrier Jumps", carriers)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.Data(200, "text/calendar; charset=utf-8", []byte(feed))
+}
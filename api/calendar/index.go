@@ -0,0 +1,15 @@
+package calendar
+
+import (
+	"ruehrstaat-backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/calendar"}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+	calendarApi := api.Group("/calendar")
+
+	calendarApi.GET("/:token/jumps.ics", getUserCalendar)
+}
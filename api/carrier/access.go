@@ -0,0 +1,69 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Returns the api token the request was authenticated with, nil for regular sessions.
+func getToken(c *gin.Context) *entities.ApiToken {
+	return auth.ApiTokenFromContext(c)
+}
+
+func isCarrierOwner(user *entities.User, cr *entities.Carrier) bool {
+	return carrier.IsOwner(user, cr)
+}
+
+func hasCarrierAccess(user *entities.User, token *entities.ApiToken, cr *entities.Carrier, scope entities.ApiTokenScope) bool {
+	return carrier.HasAccess(user, token, cr, scope)
+}
+
+func canReadCarrier(user *entities.User, token *entities.ApiToken, cr *entities.Carrier) bool {
+	return carrier.CanRead(user, token, cr)
+}
+
+func canWriteCarrier(user *entities.User, token *entities.ApiToken, cr *entities.Carrier) bool {
+	return carrier.CanWrite(user, token, cr)
+}
+
+// Whether the user (or the token acting on behalf of the user) may report ingame events of the carrier.
+func canUseConnector(user *entities.User, token *entities.ApiToken, cr *entities.Carrier) bool {
+	return hasCarrierAccess(user, token, cr, entities.ScopeCarrierConnector)
+}
+
+// Whether the request may see that a carrier does not exist, instead of getting a forbidden error.
+func canSeeAllCarriers(user *entities.User, token *entities.ApiToken, scope entities.ApiTokenScope) bool {
+	if token != nil {
+		return token.GrantsAllCarriers(scope) || (user.IsAdmin && token.HasUnrestrictedScope(scope))
+	}
+	return user.IsAdmin
+}
+
+// Limits a carrier query to the carriers the user (or token) may read.
+func readableCarriers(query *gorm.DB, user *entities.User, token *entities.ApiToken) *gorm.DB {
+	if canSeeAllCarriers(user, token, entities.ScopeCarrierRead) {
+		return query
+	}
+
+	if token == nil {
+		return query.Where("owner_id = ?", user.ID)
+	}
+
+	owned := token.HasUnrestrictedScope(entities.ScopeCarrierRead)
+	granted := token.GrantedCarriers(entities.ScopeCarrierRead)
+	switch {
+	case owned && len(granted) > 0:
+		return query.Where("owner_id = ? OR id IN ?", user.ID, granted)
+	case owned:
+		return query.Where("owner_id = ?", user.ID)
+	case len(granted) > 0:
+		// tokens restricted to single carriers only see those
+		return query.Where("id IN ?", granted)
+	default:
+		return query.Where("1 = 0")
+	}
+}
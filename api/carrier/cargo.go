@@ -0,0 +1,125 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /carrier/:id/cargo
+func getCarrierCargo(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canReadCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	cargo, err := carrier.GetCargo(cr)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"cargoSpace": cr.CargoSpace,
+		"cargoUsed":  cr.CargoUsed,
+		"cargo":      serialize.DoArray[entities.CarrierCargo](&serialize.CarrierCargoSerializer{}, cargo),
+	})
+}
+
+// PUT /carrier/:id/cargo -> replaces the whole manifest
+func replaceCarrierCargo(c *gin.Context) {
+	updateCarrierCargo(c, carrier.ReplaceCargo)
+}
+
+// PATCH /carrier/:id/cargo -> updates single entries, a quantity of 0 removes the entry
+func patchCarrierCargo(c *gin.Context) {
+	updateCarrierCargo(c, carrier.UpdateCargo)
+}
+
+func updateCarrierCargo(c *gin.Context, apply func(*entities.Carrier, []entities.CarrierCargo) ([]entities.CarrierCargo, *errors.RstError)) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canWriteCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	dto := updateCargoDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	cargo, err := apply(cr, cargoFromDto(dto.Cargo))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"cargoSpace": cr.CargoSpace,
+		"cargoUsed":  cr.CargoUsed,
+		"cargo":      serialize.DoArray[entities.CarrierCargo](&serialize.CarrierCargoSerializer{}, cargo),
+	})
+}
+
+// GET /carrier/cargo?commodity= -> lists which of the accessible carriers store the given commodity
+func findCargo(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	commodity := entities.NormalizeCommodity(c.Query("commodity"))
+	if commodity == "" {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	query := db.DB.Where("commodity = ? AND quantity > 0", commodity)
+	if !canSeeAllCarriers(user, token, entities.ScopeCarrierRead) {
+		query = query.Where("carrier_id IN (?)", readableCarriers(db.DB.Model(&entities.Carrier{}).Select("id"), user, token))
+	}
+
+	cargo := []entities.CarrierCargo{}
+	if res := query.Preload("Carrier").Preload("Carrier.Owner").Order("quantity DESC").Find(&cargo); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSONArray[entities.CarrierCargo](c, &serialize.CarrierCargoSerializer{}, cargo)
+}
+
+func cargoFromDto(entries []cargoEntryDto) []entities.CarrierCargo {
+	cargo := make([]entities.CarrierCargo, len(entries))
+	for i, entry := range entries {
+		cargo[i] = entities.CarrierCargo{
+			Commodity: entry.Commodity,
+			Quantity:  entry.Quantity,
+			Stolen:    entry.Stolen,
+			Mission:   entry.Mission,
+		}
+	}
+	return cargo
+}
@@ -6,6 +6,7 @@ import (
 	"ruehrstaat-backend/db/entities"
 	"ruehrstaat-backend/errors"
 	"ruehrstaat-backend/services/carrier"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,19 +16,19 @@ const (
 	CarrierJumpTypeCancelled = "cancel"
 )
 
+// Time between plotting a jump ingame and the actual departure
+const carrierJumpCountdown = time.Minute * 15
+
 type carrierJumpDto struct {
-	MarketID string `json:"marketId" binding:"required"`
-	Type     string `json:"type" binding:"required"`
-	Body     string `json:"body"`
+	MarketID      string     `json:"marketId" binding:"required"`
+	Type          string     `json:"type" binding:"required"`
+	Body          string     `json:"body"`
+	DepartureTime *time.Time `json:"departureTime"`
 }
 
 func carrierJump(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	// check dto
 	dto := carrierJumpDto{}
@@ -45,7 +46,7 @@ func carrierJump(c *gin.Context) {
 	// check if carrier exists using market id
 	cr := entities.Carrier{}
 	if res := db.DB.Where("market_id = ?", dto.MarketID).First(&cr); res.Error != nil {
-		if !user.IsAdmin && (token == nil || !token.HasFullWriteAccess) {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierConnector) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -53,14 +54,14 @@ func carrierJump(c *gin.Context) {
 		return
 	}
 
-	// check if user is admin or token has full write access
-	if !user.IsAdmin && (token == nil || !token.HasWriteAccessToCarrier(cr.ID)) {
-		if cr.OwnerID != nil && *cr.OwnerID != user.ID {
-			errors.ReturnWithError(c, carrier.ErrForbidden)
-			return
-		}
+	if !canUseConnector(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
 	}
 
+	before := carrier.Snapshot(&cr)
+	origin := cr.CurrentLocation
+
 	// if type "jump" -> append currentLocation to LocationHistory and set CurrentLocation to new location else if type "cancel" -> remove last entry from LocationHistory and set CurrentLocation to last entry
 	if dto.Type == CarrierJumpTypePlotted {
 		// check if body is set
@@ -85,6 +86,30 @@ func carrierJump(c *gin.Context) {
 		return
 	}
 
+	// keep the jump schedule in sync
+	if dto.Type == CarrierJumpTypePlotted {
+		departureAt := time.Now().Add(carrierJumpCountdown)
+		if dto.DepartureTime != nil {
+			departureAt = *dto.DepartureTime
+		}
+
+		if _, err := carrier.RecordPlottedJump(&cr, origin, dto.Body, departureAt); err != nil {
+			c.Error(err)
+			errors.ReturnWithError(c, carrier.ErrInternalServerError)
+			return
+		}
+
+		carrier.RecordChanges(&before, &cr)
+	} else {
+		if err := carrier.CancelPendingJump(&cr); err != nil {
+			c.Error(err)
+			errors.ReturnWithError(c, carrier.ErrInternalServerError)
+			return
+		}
+
+		carrier.RecordJumpCancelled(&cr)
+	}
+
 	c.JSON(200, gin.H{"success": true})
 }
 
@@ -95,11 +120,7 @@ type carrierDockingAccessDto struct {
 
 func updateCarrierDockingAccess(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	// check dto
 	dto := carrierDockingAccessDto{}
@@ -111,7 +132,7 @@ func updateCarrierDockingAccess(c *gin.Context) {
 	// check if carrier exists using market id
 	cr := entities.Carrier{}
 	if res := db.DB.Where("market_id = ?", dto.MarketID).First(&cr); res.Error != nil {
-		if !user.IsAdmin && (token == nil || !token.HasFullWriteAccess) {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierConnector) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -119,15 +140,13 @@ func updateCarrierDockingAccess(c *gin.Context) {
 		return
 	}
 
-	// check if user is admin or token has full write access
-	if !user.IsAdmin && (token == nil || !token.HasWriteAccessToCarrier(cr.ID)) {
-		if cr.OwnerID != nil && *cr.OwnerID != user.ID {
-			errors.ReturnWithError(c, carrier.ErrForbidden)
-			return
-		}
+	if !canUseConnector(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
 	}
 
 	// update carrier
+	before := carrier.Snapshot(&cr)
 	err := cr.SetDockingAccess(dto.Access)
 	if err != nil {
 		errors.ReturnWithError(c, err)
@@ -140,6 +159,8 @@ func updateCarrierDockingAccess(c *gin.Context) {
 		return
 	}
 
+	carrier.RecordChanges(&before, &cr)
+
 	c.JSON(200, gin.H{"success": true})
 }
 
@@ -151,11 +172,7 @@ type carrierServiceDto struct {
 
 func updateCarrierService(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	// check dto
 	dto := carrierServiceDto{}
@@ -167,7 +184,7 @@ func updateCarrierService(c *gin.Context) {
 	// check if carrier exists using market id
 	cr := entities.Carrier{}
 	if res := db.DB.Where("market_id = ?", dto.MarketID).First(&cr); res.Error != nil {
-		if !user.IsAdmin && (token == nil || !token.HasFullWriteAccess) {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierConnector) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -175,12 +192,9 @@ func updateCarrierService(c *gin.Context) {
 		return
 	}
 
-	// check if user is admin or token has full write access
-	if !user.IsAdmin && (token == nil || !token.HasWriteAccessToCarrier(cr.ID)) {
-		if cr.OwnerID != nil && *cr.OwnerID != user.ID {
-			errors.ReturnWithError(c, carrier.ErrForbidden)
-			return
-		}
+	if !canUseConnector(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
 	}
 
 	// update carrier
@@ -190,6 +204,8 @@ func updateCarrierService(c *gin.Context) {
 		return
 	}
 
+	before := carrier.Snapshot(&cr)
+
 	switch dto.Operation {
 	case "activate", "resume":
 		cr.AddService(service)
@@ -206,5 +222,47 @@ func updateCarrierService(c *gin.Context) {
 		return
 	}
 
+	carrier.RecordChanges(&before, &cr)
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+type carrierCargoDto struct {
+	MarketID string          `json:"marketId" binding:"required"`
+	Cargo    []cargoEntryDto `json:"cargo" binding:"required,dive"` // full inventory as reported ingame
+}
+
+func updateCarrierCargoFromConnector(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	// check dto
+	dto := carrierCargoDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	// check if carrier exists using market id
+	cr := entities.Carrier{}
+	if res := db.DB.Where("market_id = ?", dto.MarketID).First(&cr); res.Error != nil {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierConnector) {
+			errors.ReturnWithError(c, carrier.ErrForbidden)
+			return
+		}
+		errors.ReturnWithError(c, carrier.ErrCarrierNotFound)
+		return
+	}
+
+	if !canUseConnector(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	if _, err := carrier.ReplaceCargo(&cr, cargoFromDto(dto.Cargo)); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
 	c.JSON(200, gin.H{"success": true})
 }
@@ -1,6 +1,8 @@
 package carrier
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -26,6 +28,12 @@ type createCarrierDto struct {
 	OwnerID *uuid.UUID `json:"ownerId"`
 
 	Category string `json:"category"`
+
+	IsPublic        bool `json:"isPublic"`
+	ShowFuelOnBadge bool `json:"showFuelOnBadge"`
+	PublishToEddn   bool `json:"publishToEddn"`
+	SyncToInara     bool `json:"syncToInara"`
+	SyncToEdsm      bool `json:"syncToEdsm"`
 }
 
 type updateCarrierOverrideDto struct {
@@ -52,6 +60,12 @@ type updateCarrierOverrideDto struct {
 	OwnerID *uuid.UUID `json:"ownerId"`
 
 	Category string `json:"category"`
+
+	IsPublic        bool `json:"isPublic"`
+	ShowFuelOnBadge bool `json:"showFuelOnBadge"`
+	PublishToEddn   bool `json:"publishToEddn"`
+	SyncToInara     bool `json:"syncToInara"`
+	SyncToEdsm      bool `json:"syncToEdsm"`
 }
 
 type updateCarrierDto struct {
@@ -79,4 +93,32 @@ type updateCarrierDto struct {
 	OwnerID *uuid.UUID `json:"ownerId"`
 
 	Category *string `json:"category"`
+
+	IsPublic        *bool `json:"isPublic"`
+	ShowFuelOnBadge *bool `json:"showFuelOnBadge"`
+	PublishToEddn   *bool `json:"publishToEddn"`
+	SyncToInara     *bool `json:"syncToInara"`
+	SyncToEdsm      *bool `json:"syncToEdsm"`
+}
+
+type cargoEntryDto struct {
+	Commodity string `json:"commodity" binding:"required"`
+	Quantity  int    `json:"quantity" binding:"min=0"`
+	Stolen    bool   `json:"stolen"`
+	Mission   bool   `json:"mission"`
+}
+
+type updateCargoDto struct {
+	Cargo []cargoEntryDto `json:"cargo" binding:"required,dive"`
+}
+
+type createProposalDto struct {
+	Destination  string     `json:"destination" binding:"required"`
+	DepartureAt  *time.Time `json:"departureAt"`
+	Note         string     `json:"note"`
+	VotingEndsAt *time.Time `json:"votingEndsAt"`
+}
+
+type approveProposalDto struct {
+	DepartureAt *time.Time `json:"departureAt"`
 }
@@ -15,38 +15,13 @@ import (
 
 func getAllCarriers(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	carriers := []entities.Carrier{}
-
-	if user.IsAdmin || (token != nil && token.HasFullReadAccess) {
-		if res := db.DB.Find(&carriers).Preload("Owner"); res.Error != nil {
-			c.Error(res.Error)
-			errors.ReturnWithError(c, carrier.ErrInternalServerError)
-			return
-		}
-	} else {
-		// get carrier where owner id is user id
-		if res := db.DB.Where("owner_id = ?", user.ID).Preload("Owner").Find(&carriers); res.Error != nil {
-			c.Error(res.Error)
-			errors.ReturnWithError(c, carrier.ErrInternalServerError)
-			return
-		}
-
-		// if token is not nil, get append carriers where id is in token.HadReadAccessTo
-		if token != nil {
-			addtionalCarriers := []entities.Carrier{}
-			if res := db.DB.Where("id IN (?)", token.HasReadAccessTo).Preload("Owner").Find(&addtionalCarriers); res.Error != nil {
-				c.Error(res.Error)
-				errors.ReturnWithError(c, carrier.ErrInternalServerError)
-				return
-			}
-			carriers = append(carriers, addtionalCarriers...)
-		}
+	if res := readableCarriers(db.DB, user, token).Preload("Owner").Find(&carriers); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
 	}
 
 	serialize.JSONArray[entities.Carrier](c, (&serialize.CarrierSerializer{}).ParseFlags(c), carriers)
@@ -54,11 +29,7 @@ func getAllCarriers(c *gin.Context) {
 
 func getCarrier(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	carrierId := c.Param("id")
 	if carrierId == "" {
@@ -68,7 +39,7 @@ func getCarrier(c *gin.Context) {
 
 	cr := entities.Carrier{}
 	if res := db.DB.Where("id = ?", carrierId).Preload("Owner").First(&cr); res.Error != nil {
-		if !user.IsAdmin {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierRead) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -76,11 +47,9 @@ func getCarrier(c *gin.Context) {
 		return
 	}
 
-	if !user.IsAdmin && (token == nil || !token.HasFullReadAccess) {
-		if *cr.OwnerID != user.ID && !token.HasReadAccessToCarrier(cr.ID) {
-			errors.ReturnWithError(c, carrier.ErrForbidden)
-			return
-		}
+	if !canReadCarrier(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
 	}
 
 	serialize.JSON[entities.Carrier](c, (&serialize.CarrierSerializer{}).ParseFlags(c), cr)
@@ -2,7 +2,6 @@ package carrier
 
 import (
 	"ruehrstaat-backend/auth"
-	"ruehrstaat-backend/db"
 	"ruehrstaat-backend/db/entities"
 	"ruehrstaat-backend/errors"
 	"ruehrstaat-backend/logging"
@@ -17,49 +16,98 @@ func RegisterRoutes(api *gin.RouterGroup) {
 	carrierApi := api.Group("/carrier")
 	carrierApi.Use(carrierTokenAuthMiddleware())
 
-	carrierApi.GET("/", getAllCarriers)
-	carrierApi.GET("/:id", getCarrier)
-	carrierApi.POST("/", createCarrier)
-	carrierApi.PUT("/:id", updateCarrierOverride)
-	carrierApi.PATCH("/:id", updateCarrier)
-	carrierApi.HEAD("/:id", checkIfEditedSince)
+	carrierApi.GET("/", auth.RequireScope(entities.ScopeCarrierRead), getAllCarriers)
+	carrierApi.GET("/:id", auth.RequireScope(entities.ScopeCarrierRead), getCarrier)
+	carrierApi.POST("/", auth.RequireScope(entities.ScopeCarrierWrite), createCarrier)
+	carrierApi.PUT("/:id", auth.RequireScope(entities.ScopeCarrierWrite), updateCarrierOverride)
+	carrierApi.PATCH("/:id", auth.RequireScope(entities.ScopeCarrierWrite), updateCarrier)
+	carrierApi.HEAD("/:id", auth.RequireScope(entities.ScopeCarrierRead), checkIfEditedSince)
 
-	carrierApi.GET("/service", getAllServices)
-	carrierApi.GET("/service/:name", getCarrierService)
+	carrierApi.GET("/:id/jumps", auth.RequireScope(entities.ScopeCarrierRead), getCarrierJumps)
+	carrierApi.POST("/:id/jumps", auth.RequireScope(entities.ScopeCarrierWrite), createCarrierJump)
+	carrierApi.DELETE("/:id/jumps/:jumpId", auth.RequireScope(entities.ScopeCarrierWrite), cancelCarrierJump)
+
+	carrierApi.GET("/:id/cargo", auth.RequireScope(entities.ScopeCarrierRead), getCarrierCargo)
+	carrierApi.PUT("/:id/cargo", auth.RequireScope(entities.ScopeCarrierWrite), replaceCarrierCargo)
+	carrierApi.PATCH("/:id/cargo", auth.RequireScope(entities.ScopeCarrierWrite), patchCarrierCargo)
+	carrierApi.GET("/cargo", auth.RequireScope(entities.ScopeCarrierRead), findCargo)
+
+	carrierApi.GET("/:id/logistics", auth.RequireScope(entities.ScopeCarrierRead), getCarrierLogisticsRequests)
+	carrierApi.GET("/:id/aboard", auth.RequireScope(entities.ScopeCarrierRead), getAboard)
+	carrierApi.GET("/:id/sync", auth.RequireScope(entities.ScopeCarrierWrite), getCarrierSyncJobs)
+
+	carrierApi.GET("/service", auth.RequireScope(entities.ScopeCarrierRead), getAllServices)
+	carrierApi.GET("/service/:name", auth.RequireScope(entities.ScopeCarrierRead), getCarrierService)
 
 	connectorApi := carrierApi.Group("/connector")
-	connectorApi.PUT("/jump", carrierJump)
-	connectorApi.PUT("/access", updateCarrierDockingAccess)
-	connectorApi.PUT("/service", updateCarrierService)
+	connectorApi.PUT("/jump", auth.RequireScope(entities.ScopeCarrierConnector), carrierJump)
+	connectorApi.PUT("/access", auth.RequireScope(entities.ScopeCarrierConnector), updateCarrierDockingAccess)
+	connectorApi.PUT("/service", auth.RequireScope(entities.ScopeCarrierConnector), updateCarrierService)
+	connectorApi.PUT("/cargo", auth.RequireScope(entities.ScopeCarrierConnector), updateCarrierCargoFromConnector)
+	connectorApi.PUT("/presence", auth.RequireScope(entities.ScopeCarrierConnector), updatePresence)
+	connectorApi.PUT("/market", auth.RequireScope(entities.ScopeCarrierConnector), updateCarrierMarket)
 
+	// proposals are open to every member, not only to the carrier owner
+	proposalApi := api.Group("/carrier/:id/proposals")
+	proposalApi.Use(carrierMemberAuthMiddleware())
+
+	proposalApi.GET("", auth.RequireScope(entities.ScopeCarrierRead), getJumpProposals)
+	proposalApi.POST("", auth.RequireScope(entities.ScopeCarrierWrite), createJumpProposal)
+	proposalApi.GET("/:proposalId", auth.RequireScope(entities.ScopeCarrierRead), getJumpProposal)
+	proposalApi.DELETE("/:proposalId", auth.RequireScope(entities.ScopeCarrierWrite), closeJumpProposal)
+	proposalApi.PUT("/:proposalId/vote", auth.RequireScope(entities.ScopeCarrierWrite), voteJumpProposal)
+	proposalApi.DELETE("/:proposalId/vote", auth.RequireScope(entities.ScopeCarrierWrite), unvoteJumpProposal)
+	proposalApi.POST("/:proposalId/approve", auth.RequireScope(entities.ScopeCarrierWrite), approveJumpProposal)
 }
 
 func carrierTokenAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// check if "X-RST-User-Id" and "X-RST-Token" headers are set
-		if c.GetHeader("X-RST-User-Id") != "" {
-			token := auth.AuthenticateApiToken(c)
+		// check if "X-RST-User-Id" and "X-RST-Token" headers or an oauth access token are set
+		if auth.HasApiTokenCredentials(c) {
+			user, token := auth.AuthenticateApiTokenUser(c)
 			if token == nil {
 				errors.MiddlewareAbortWithError(c, carrier.ErrUnauthorized)
 				return
 			}
 
 			c.Set("token", token)
+			c.Set("user", user)
+		} else {
+			current, authorized := auth.Authorize(c)
+			if !authorized {
+				errors.MiddlewareAbortWithError(c, carrier.ErrUnauthorized)
+				return
+			}
+
+			c.Set("user", current)
+		}
+	}
+}
 
-			user := &entities.User{}
-			if res := db.DB.Where("id = ?", &token.UserID).First(user); res.Error != nil {
+// Accepts api tokens as well as the sessions of every member, not only of admins.
+func carrierMemberAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.HasApiTokenCredentials(c) {
+			user, token := auth.AuthenticateApiTokenUser(c)
+			if token == nil {
 				errors.MiddlewareAbortWithError(c, carrier.ErrUnauthorized)
 				return
 			}
 
+			c.Set("token", token)
 			c.Set("user", user)
 		} else {
-			current, authorized := auth.Authorize(c)
-			if !authorized {
+			current := auth.Extract(c)
+			if current == nil {
 				errors.MiddlewareAbortWithError(c, carrier.ErrUnauthorized)
 				return
 			}
 
+			if current.IsBanned {
+				errors.MiddlewareAbortWithError(c, carrier.ErrForbidden)
+				return
+			}
+
 			c.Set("user", current)
 		}
 	}
@@ -0,0 +1,156 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type createCarrierJumpDto struct {
+	Destination string    `json:"destination" binding:"required"`
+	DepartureAt time.Time `json:"departureAt" binding:"required"`
+	Note        string    `json:"note"`
+}
+
+// GET /carrier/:id/jumps
+func getCarrierJumps(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canReadCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	query := db.DB.Where("carrier_id = ?", cr.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	jumps := []entities.CarrierJump{}
+	if res := query.Order("departure_at DESC").Limit(100).Find(&jumps); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSONArray[entities.CarrierJump](c, &serialize.CarrierJumpSerializer{}, jumps)
+}
+
+// POST /carrier/:id/jumps -> schedules a planned jump
+func createCarrierJump(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canWriteCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	dto := createCarrierJumpDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.DepartureAt.Before(time.Now()) {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	jump := entities.CarrierJump{
+		CarrierID:   cr.ID,
+		Origin:      carrier.LocationAt(cr, dto.DepartureAt),
+		Destination: dto.Destination,
+		DepartureAt: dto.DepartureAt,
+		Note:        dto.Note,
+		Status:      entities.CarrierJumpStatusPlanned,
+		CreatedByID: &user.ID,
+	}
+
+	if res := db.DB.Create(&jump); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSON[entities.CarrierJump](c, &serialize.CarrierJumpSerializer{}, jump)
+}
+
+// DELETE /carrier/:id/jumps/:jumpId -> cancels a scheduled jump
+func cancelCarrierJump(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canWriteCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	jumpId, parseErr := uuid.Parse(c.Param("jumpId"))
+	if parseErr != nil {
+		errors.ReturnWithError(c, carrier.ErrInvalidCarrierJumpId)
+		return
+	}
+
+	jump := entities.CarrierJump{}
+	if res := db.DB.Where("id = ? AND carrier_id = ?", jumpId, cr.ID).First(&jump); res.Error != nil {
+		errors.ReturnWithError(c, carrier.ErrCarrierJumpNotFound)
+		return
+	}
+
+	if !jump.IsScheduled() {
+		errors.ReturnWithError(c, carrier.ErrCarrierJumpNotScheduled)
+		return
+	}
+
+	jump.Status = entities.CarrierJumpStatusCancelled
+	if res := db.DB.Save(&jump); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// Loads the carrier referenced by the :id route param.
+func findCarrierByParam(c *gin.Context) (*entities.Carrier, *errors.RstError) {
+	carrierId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, carrier.ErrInvalidCarrierId
+	}
+
+	cr := &entities.Carrier{}
+	if res := db.DB.Where("id = ?", carrierId).Preload("Owner").First(cr); res.Error != nil {
+		return nil, carrier.ErrCarrierNotFound
+	}
+
+	return cr, nil
+}
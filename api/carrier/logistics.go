@@ -0,0 +1,36 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/logistics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /carrier/:id/logistics -> logistics requests assigned to the carrier
+func getCarrierLogisticsRequests(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canReadCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	requests, err := logistics.ListForCarrier(cr, c.Query("status"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, requests)
+}
@@ -0,0 +1,119 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/eddn"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type marketItemDto struct {
+	Name          string `json:"name" binding:"required"`
+	Category      string `json:"category"`
+	BuyPrice      int    `json:"buyPrice"`
+	SellPrice     int    `json:"sellPrice"`
+	MeanPrice     int    `json:"meanPrice"`
+	Stock         int    `json:"stock"`
+	StockBracket  int    `json:"stockBracket"`
+	Demand        int    `json:"demand"`
+	DemandBracket int    `json:"demandBracket"`
+}
+
+// Content of the Market.json file written by the game
+type carrierMarketDto struct {
+	MarketID   string          `json:"marketId" binding:"required"`
+	StarSystem string          `json:"starSystem" binding:"required"`
+	Timestamp  time.Time       `json:"timestamp" binding:"required"`
+	Items      []marketItemDto `json:"items" binding:"dive"`
+}
+
+// PUT /carrier/connector/market -> forwards the market orders of the carrier to EDDN if the carrier opted in
+func updateCarrierMarket(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	// check dto
+	dto := carrierMarketDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	// check if carrier exists using market id
+	cr := entities.Carrier{}
+	if res := db.DB.Where("market_id = ?", dto.MarketID).First(&cr); res.Error != nil {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierConnector) {
+			errors.ReturnWithError(c, carrier.ErrForbidden)
+			return
+		}
+		errors.ReturnWithError(c, carrier.ErrCarrierNotFound)
+		return
+	}
+
+	if !canUseConnector(user, token, &cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	if !cr.PublishToEddn {
+		c.JSON(200, gin.H{"success": true, "published": false})
+		return
+	}
+
+	marketID, err := strconv.ParseInt(cr.MarketID, 10, 64)
+	if err != nil {
+		errors.ReturnWithError(c, carrier.ErrInvalidMarketData)
+		return
+	}
+
+	message := &eddn.CommodityMessage{
+		SystemName:           dto.StarSystem,
+		StationName:          cr.Callsign,
+		StationType:          "FleetCarrier",
+		CarrierDockingAccess: string(cr.DockingAccess),
+		MarketID:             marketID,
+		Horizons:             true,
+		Odyssey:              true,
+		Timestamp:            dto.Timestamp.UTC(),
+		Commodities:          []eddn.Commodity{},
+	}
+
+	for _, item := range dto.Items {
+		// EDDN only accepts regular market goods
+		if item.Category == "NonMarketable" || entities.NormalizeCommodity(item.Category) == "nonmarketable" {
+			continue
+		}
+
+		message.Commodities = append(message.Commodities, eddn.Commodity{
+			Name:          entities.NormalizeCommodity(item.Name),
+			MeanPrice:     item.MeanPrice,
+			BuyPrice:      item.BuyPrice,
+			Stock:         item.Stock,
+			StockBracket:  item.StockBracket,
+			SellPrice:     item.SellPrice,
+			Demand:        item.Demand,
+			DemandBracket: item.DemandBracket,
+		})
+	}
+
+	if err := message.Validate(); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInvalidMarketData)
+		return
+	}
+
+	// uploading may take a while with retries, the connector does not have to wait for it
+	if err := eddn.DefaultPublisher().EnqueueCommodity(user.CmdrName, message); err != nil {
+		log.Println("Could not publish market of carrier", cr.ID, "to EDDN:", err)
+		c.JSON(200, gin.H{"success": true, "published": false})
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true, "published": true})
+}
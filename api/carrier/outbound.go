@@ -0,0 +1,36 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /carrier/:id/sync -> pending and failed pushes to Inara and EDSM
+func getCarrierSyncJobs(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canWriteCarrier(user, getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	jobs := []entities.CarrierSyncJob{}
+	if res := db.DB.Where("carrier_id = ?", cr.ID).Order("occurred_at DESC").Limit(100).Find(&jobs); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSONArray[entities.CarrierSyncJob](c, &serialize.CarrierSyncJobSerializer{}, jobs)
+}
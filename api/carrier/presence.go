@@ -0,0 +1,105 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/presence"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	PresenceEventDocked      = "Docked"
+	PresenceEventUndocked    = "Undocked"
+	PresenceEventCarrierJump = "CarrierJump"
+)
+
+type presenceDto struct {
+	Event    string `json:"event" binding:"required"` // Docked, Undocked or CarrierJump journal event of the commander
+	MarketID string `json:"marketId"`
+}
+
+// PUT /carrier/connector/presence -> reports where the commander of the current user is docked
+func updatePresence(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	dto := presenceDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.Event != PresenceEventDocked && dto.Event != PresenceEventUndocked && dto.Event != PresenceEventCarrierJump {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	// docking anywhere else than on one of our carriers means leaving the carrier
+	cr := entities.Carrier{}
+	aboard := false
+	if dto.Event != PresenceEventUndocked && dto.MarketID != "" && !user.HidePresence {
+		res := db.DB.Where("market_id = ?", dto.MarketID).Limit(1).Find(&cr)
+		if res.Error != nil {
+			c.Error(res.Error)
+			errors.ReturnWithError(c, carrier.ErrInternalServerError)
+			return
+		}
+		aboard = res.RowsAffected > 0
+	}
+
+	var err error
+	if aboard {
+		err = presence.Board(user.ID, cr.ID)
+	} else {
+		err = presence.Leave(user.ID)
+	}
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true, "tracked": !user.HidePresence})
+}
+
+// GET /carrier/:id/aboard -> commanders currently aboard, users that opted out are left out
+func getAboard(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canReadCarrier(user, getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	userIds, aboardErr := presence.Aboard(cr.ID)
+	if aboardErr != nil {
+		c.Error(aboardErr)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	users := []entities.User{}
+	if len(userIds) > 0 {
+		if res := db.DB.Where("id IN ? AND hide_presence = ?", userIds, false).Order("cmdr_name").Find(&users); res.Error != nil {
+			c.Error(res.Error)
+			errors.ReturnWithError(c, carrier.ErrInternalServerError)
+			return
+		}
+	}
+
+	aboard := []gin.H{}
+	for _, commander := range users {
+		aboard = append(aboard, gin.H{"id": commander.ID, "cmdrName": commander.CmdrName})
+	}
+
+	c.JSON(200, gin.H{"count": len(aboard), "commanders": aboard})
+}
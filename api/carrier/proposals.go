@@ -0,0 +1,226 @@
+package carrier
+
+import (
+	"io"
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /carrier/:id/proposals -> destination proposals, ?status= filters, open to every member
+func getJumpProposals(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	proposals, err := carrier.ListProposals(cr, c.Query("status"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, proposals)
+}
+
+// POST /carrier/:id/proposals -> proposes a destination, open to every member
+func createJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	dto := createProposalDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	proposal, err := carrier.CreateProposal(cr, user, dto.Destination, dto.DepartureAt, dto.Note, dto.VotingEndsAt)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal)
+}
+
+// GET /carrier/:id/proposals/:proposalId
+func getJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, proposal, err := findProposalByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	serialize.JSON[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal)
+}
+
+// DELETE /carrier/:id/proposals/:proposalId -> withdrawn by the proposer or rejected by the carrier owner
+func closeJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, proposal, err := findProposalByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	status := entities.JumpProposalStatusWithdrawn
+	if proposal.ProposedByID != user.ID {
+		if !canWriteCarrier(user, token, cr) {
+			errors.ReturnWithError(c, carrier.ErrForbidden)
+			return
+		}
+		status = entities.JumpProposalStatusRejected
+	}
+
+	if err := carrier.CloseProposal(proposal, status); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal)
+}
+
+// PUT /carrier/:id/proposals/:proposalId/vote
+func voteJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, proposal, err := findProposalByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	if err := carrier.Vote(proposal, user); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal)
+}
+
+// DELETE /carrier/:id/proposals/:proposalId/vote
+func unvoteJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	cr, proposal, err := findProposalByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !tokenCoversCarrier(getToken(c), cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	if err := carrier.RemoveVote(proposal, user); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.JumpProposal](c, &serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal)
+}
+
+// POST /carrier/:id/proposals/:proposalId/approve -> schedules the jump, only for the carrier owner
+func approveJumpProposal(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+	token := getToken(c)
+
+	cr, proposal, err := findProposalByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canWriteCarrier(user, token, cr) {
+		errors.ReturnWithError(c, carrier.ErrForbidden)
+		return
+	}
+
+	// the departure is optional if the proposal already suggests one
+	dto := approveProposalDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil && err != io.EOF {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	jump, err := carrier.ApproveProposal(cr, proposal, user, dto.DepartureAt)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"proposal": serialize.Do[entities.JumpProposal](&serialize.JumpProposalSerializer{UserID: &user.ID}, *proposal),
+		"jump":     serialize.Do[entities.CarrierJump](&serialize.CarrierJumpSerializer{}, *jump),
+	})
+}
+
+// Members take part without access to the carrier, but tokens still have to be issued for it.
+func tokenCoversCarrier(token *entities.ApiToken, cr *entities.Carrier) bool {
+	return token == nil || token.CoversCarrier(entities.ScopeCarrierWrite, cr.ID)
+}
+
+// Loads the carrier and proposal referenced by the :id and :proposalId route params.
+func findProposalByParam(c *gin.Context) (*entities.Carrier, *entities.JumpProposal, *errors.RstError) {
+	cr, err := findCarrierByParam(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proposalId, parseErr := uuid.Parse(c.Param("proposalId"))
+	if parseErr != nil {
+		return nil, nil, carrier.ErrInvalidProposalId
+	}
+
+	proposal, err := carrier.FindProposal(cr, proposalId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cr, proposal, nil
+}
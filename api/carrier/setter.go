@@ -14,14 +14,10 @@ import (
 
 func createCarrier(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
-	// check if user is admin or token has full write access
-	if !user.IsAdmin && (token == nil || !token.HasFullWriteAccess) {
+	// check if user is admin or token has write access to all carriers
+	if !canSeeAllCarriers(user, token, entities.ScopeCarrierWrite) {
 		errors.ReturnWithError(c, carrier.ErrForbidden)
 		return
 	}
@@ -44,6 +40,11 @@ func createCarrier(c *gin.Context) {
 		Callsign:        carrierDto.Callsign,
 		CurrentLocation: carrierDto.CurrentLocation,
 		AllowNotorious:  carrierDto.AllowNotorious,
+		IsPublic:        carrierDto.IsPublic,
+		ShowFuelOnBadge: carrierDto.ShowFuelOnBadge,
+		PublishToEddn:   carrierDto.PublishToEddn,
+		SyncToInara:     carrierDto.SyncToInara,
+		SyncToEdsm:      carrierDto.SyncToEdsm,
 		Services:        []entities.CarrierService{},
 		FuelLevel:       carrierDto.FuelLevel,
 		CargoSpace:      carrierDto.CargoSpace,
@@ -82,6 +83,11 @@ func createCarrier(c *gin.Context) {
 		return
 	}
 
+	if cr.CargoUsed < 0 || cr.CargoUsed > cr.CargoSpace {
+		errors.ReturnWithError(c, carrier.ErrCargoExceedsCapacity)
+		return
+	}
+
 	if res := db.DB.Create(&cr); res.Error != nil {
 		errors.ReturnWithError(c, carrier.ErrInternalServerError)
 		return
@@ -92,11 +98,7 @@ func createCarrier(c *gin.Context) {
 
 func updateCarrierOverride(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	carrierIdStr := c.Param("id")
 	if carrierIdStr == "" {
@@ -112,7 +114,7 @@ func updateCarrierOverride(c *gin.Context) {
 
 	cr := entities.Carrier{}
 	if res := db.DB.Where("id = ?", carrierId).First(&cr); res.Error != nil {
-		if !user.IsAdmin || (token == nil || !token.HasFullWriteAccess) {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierWrite) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -121,7 +123,7 @@ func updateCarrierOverride(c *gin.Context) {
 	}
 
 	// check if user is admin, owner or token has write access
-	if !user.IsAdmin && !(cr.OwnerID != nil && user.ID == *cr.OwnerID) && (token == nil || !token.HasWriteAccessToCarrier(carrierId)) {
+	if !canWriteCarrier(user, token, &cr) {
 		errors.ReturnWithError(c, carrier.ErrForbidden)
 		return
 	}
@@ -138,6 +140,8 @@ func updateCarrierOverride(c *gin.Context) {
 		return
 	}
 
+	before := carrier.Snapshot(&cr)
+
 	// update Carrier
 	cr.MarketID = carrierDto.MarketID
 	cr.Name = carrierDto.Name
@@ -147,6 +151,11 @@ func updateCarrierOverride(c *gin.Context) {
 		cr.LocationHistory = carrierDto.LocationHistory
 	}
 	cr.AllowNotorious = carrierDto.AllowNotorious
+	cr.IsPublic = carrierDto.IsPublic
+	cr.ShowFuelOnBadge = carrierDto.ShowFuelOnBadge
+	cr.PublishToEddn = carrierDto.PublishToEddn
+	cr.SyncToInara = carrierDto.SyncToInara
+	cr.SyncToEdsm = carrierDto.SyncToEdsm
 	cr.FuelLevel = carrierDto.FuelLevel
 	cr.CargoSpace = carrierDto.CargoSpace
 	cr.CargoUsed = carrierDto.CargoUsed
@@ -187,22 +196,25 @@ func updateCarrierOverride(c *gin.Context) {
 		return
 	}
 
+	if err := carrier.CheckCargoCapacity(&cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
 	if res := db.DB.Save(&cr); res.Error != nil {
 		errors.ReturnWithError(c, carrier.ErrInternalServerError)
 		return
 	}
 
+	carrier.RecordChanges(&before, &cr)
+
 	serialize.JSON[entities.Carrier](c, (&serialize.CarrierSerializer{}).ParseFlags(c), cr)
 }
 
 // PATCH /api/carrier/:id
 func updateCarrier(c *gin.Context) {
 	user := c.MustGet("user").(*entities.User)
-	tokenValue, exists := c.Get("token")
-	token := &entities.ApiToken{}
-	if exists {
-		token = tokenValue.(*entities.ApiToken)
-	}
+	token := getToken(c)
 
 	carrierIdStr := c.Param("id")
 	if carrierIdStr == "" {
@@ -218,7 +230,7 @@ func updateCarrier(c *gin.Context) {
 
 	cr := entities.Carrier{}
 	if res := db.DB.Where("id = ?", carrierId).First(&cr); res.Error != nil {
-		if !user.IsAdmin || (token == nil || !token.HasFullWriteAccess) {
+		if !canSeeAllCarriers(user, token, entities.ScopeCarrierWrite) {
 			errors.ReturnWithError(c, carrier.ErrForbidden)
 			return
 		}
@@ -227,7 +239,7 @@ func updateCarrier(c *gin.Context) {
 	}
 
 	// check if user is admin, owner or token has write access
-	if !user.IsAdmin && !(cr.OwnerID != nil && user.ID == *cr.OwnerID) && (token == nil || !token.HasWriteAccessToCarrier(carrierId)) {
+	if !canWriteCarrier(user, token, &cr) {
 		errors.ReturnWithError(c, carrier.ErrForbidden)
 		return
 	}
@@ -238,6 +250,7 @@ func updateCarrier(c *gin.Context) {
 		return
 	}
 
+	before := carrier.Snapshot(&cr)
 	marketOrCallsignChanged := false
 
 	// update Carrier
@@ -282,6 +295,26 @@ func updateCarrier(c *gin.Context) {
 		cr.AllowNotorious = *carrierDto.AllowNotorious
 	}
 
+	if carrierDto.IsPublic != nil {
+		cr.IsPublic = *carrierDto.IsPublic
+	}
+
+	if carrierDto.ShowFuelOnBadge != nil {
+		cr.ShowFuelOnBadge = *carrierDto.ShowFuelOnBadge
+	}
+
+	if carrierDto.PublishToEddn != nil {
+		cr.PublishToEddn = *carrierDto.PublishToEddn
+	}
+
+	if carrierDto.SyncToInara != nil {
+		cr.SyncToInara = *carrierDto.SyncToInara
+	}
+
+	if carrierDto.SyncToEdsm != nil {
+		cr.SyncToEdsm = *carrierDto.SyncToEdsm
+	}
+
 	if carrierDto.Services != nil {
 		if err := cr.SetServices(*carrierDto.Services, carrierDto.OverideServices); err != nil {
 			errors.ReturnWithError(c, carrier.ErrInvalidCarrierServices)
@@ -309,7 +342,7 @@ func updateCarrier(c *gin.Context) {
 		cr.ReserveBalance = *carrierDto.ReserveBalance
 	}
 
-	if carrierDto.OwnerID != nil && (user.IsAdmin || (token != nil || token.HasFullWriteAccess)) {
+	if carrierDto.OwnerID != nil && canSeeAllCarriers(user, token, entities.ScopeCarrierWrite) {
 		// if exists set owner and owner id
 		user := entities.User{}
 		if res := db.DB.Where("id = ?", carrierDto.OwnerID).First(&user); res.Error != nil {
@@ -328,10 +361,17 @@ func updateCarrier(c *gin.Context) {
 		}
 	}
 
+	if err := carrier.CheckCargoCapacity(&cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
 	if res := db.DB.Save(&cr); res.Error != nil {
 		errors.ReturnWithError(c, carrier.ErrInternalServerError)
 		return
 	}
 
+	carrier.RecordChanges(&before, &cr)
+
 	serialize.JSON[entities.Carrier](c, (&serialize.CarrierSerializer{}).ParseFlags(c), cr)
 }
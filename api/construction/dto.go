@@ -0,0 +1,44 @@
+package construction
+
+type resourceDto struct {
+	Commodity      string `json:"commodity" binding:"required"`
+	Label          string `json:"label"`
+	RequiredAmount int    `json:"requiredAmount" binding:"min=0"`
+	ProvidedAmount int    `json:"providedAmount" binding:"min=0"`
+}
+
+type createSiteDto struct {
+	MarketID     *string       `json:"marketId"`
+	System       string        `json:"system" binding:"required"`
+	Name         string        `json:"name" binding:"required"`
+	Requirements []resourceDto `json:"requirements" binding:"dive"`
+}
+
+type updateSiteDto struct {
+	MarketID   *string  `json:"marketId"`
+	System     *string  `json:"system"`
+	Name       *string  `json:"name"`
+	Progress   *float64 `json:"progress" binding:"omitempty,min=0,max=1"`
+	IsComplete *bool    `json:"isComplete"`
+	IsFailed   *bool    `json:"isFailed"`
+}
+
+type setRequirementsDto struct {
+	Requirements []resourceDto `json:"requirements" binding:"required,dive"`
+}
+
+// Content of the ColonisationConstructionDepot journal event, enriched with the location of the last Docked event
+type depotDto struct {
+	MarketID             string  `json:"marketId" binding:"required"`
+	System               string  `json:"system"`
+	StationName          string  `json:"stationName"`
+	ConstructionProgress float64 `json:"constructionProgress" binding:"min=0,max=1"`
+	ConstructionComplete bool    `json:"constructionComplete"`
+	ConstructionFailed   bool    `json:"constructionFailed"`
+	ResourcesRequired    []struct {
+		Name           string `json:"name" binding:"required"`
+		NameLocalised  string `json:"nameLocalised"`
+		RequiredAmount int    `json:"requiredAmount" binding:"min=0"`
+		ProvidedAmount int    `json:"providedAmount" binding:"min=0"`
+	} `json:"resourcesRequired" binding:"dive"`
+}
@@ -0,0 +1,61 @@
+package construction
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/services/construction"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/construction"}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+	constructionApi := api.Group("/construction")
+	constructionApi.Use(constructionAuthMiddleware())
+
+	constructionApi.GET("/", auth.RequireScope(entities.ScopeConstructionRead), getSites)
+	constructionApi.POST("/", auth.RequireScope(entities.ScopeConstructionWrite), createSite)
+	constructionApi.GET("/summary", auth.RequireScope(entities.ScopeConstructionRead), getSummaries)
+	constructionApi.GET("/:id", auth.RequireScope(entities.ScopeConstructionRead), getSite)
+	constructionApi.PATCH("/:id", auth.RequireScope(entities.ScopeConstructionWrite), updateSite)
+	constructionApi.DELETE("/:id", auth.RequireScope(entities.ScopeConstructionWrite), deleteSite)
+	constructionApi.GET("/:id/summary", auth.RequireScope(entities.ScopeConstructionRead), getSiteSummary)
+	constructionApi.PUT("/:id/requirements", auth.RequireScope(entities.ScopeConstructionWrite), setSiteRequirements)
+	constructionApi.PUT("/:id/carriers/:carrierId", auth.RequireScope(entities.ScopeConstructionWrite), linkCarrier)
+	constructionApi.DELETE("/:id/carriers/:carrierId", auth.RequireScope(entities.ScopeConstructionWrite), unlinkCarrier)
+
+	connectorApi := constructionApi.Group("/connector")
+	connectorApi.PUT("/depot", auth.RequireScope(entities.ScopeCarrierConnector), updateDepot)
+}
+
+// Accepts connector api tokens as well as regular sessions, like the carrier api.
+func constructionAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth.HasApiTokenCredentials(c) {
+			user, token := auth.AuthenticateApiTokenUser(c)
+			if token == nil {
+				errors.MiddlewareAbortWithError(c, construction.ErrUnauthorized)
+				return
+			}
+
+			c.Set("token", token)
+			c.Set("user", user)
+		} else {
+			current := auth.Extract(c)
+			if current == nil {
+				errors.MiddlewareAbortWithError(c, construction.ErrUnauthorized)
+				return
+			}
+
+			if current.IsBanned {
+				errors.MiddlewareAbortWithError(c, construction.ErrForbidden)
+				return
+			}
+
+			c.Set("user", current)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package construction
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/construction"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GET /construction/:id/summary -> remaining needs of the site and which linked carriers hold them
+func getSiteSummary(c *gin.Context) {
+	site, err := findSiteByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	summary, err := summarizeReadable(c, site)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[construction.Summary](c, &serialize.ConstructionSummarySerializer{}, *summary)
+}
+
+// GET /construction/summary -> progress summaries of all active sites
+func getSummaries(c *gin.Context) {
+	sites := []entities.ConstructionSite{}
+	res := db.DB.Where("is_complete = ? AND is_failed = ?", false, false).
+		Preload("Requirements", func(tx *gorm.DB) *gorm.DB { return tx.Order("commodity") }).
+		Preload("Carriers").
+		Order("created_at DESC").
+		Find(&sites)
+	if res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	summaries := []construction.Summary{}
+	for i := range sites {
+		summary, err := summarizeReadable(c, &sites[i])
+		if err != nil {
+			errors.ReturnWithError(c, err)
+			return
+		}
+		summaries = append(summaries, *summary)
+	}
+
+	serialize.JSONArray[construction.Summary](c, &serialize.ConstructionSummarySerializer{}, summaries)
+}
+
+// Summarizes the site with only the linked carriers the request may read, others' cargo stays hidden.
+func summarizeReadable(c *gin.Context, site *entities.ConstructionSite) (*construction.Summary, *errors.RstError) {
+	user := c.MustGet("user").(*entities.User)
+	token := auth.ApiTokenFromContext(c)
+
+	readable := *site
+	readable.Carriers = []entities.Carrier{}
+	for _, cr := range site.Carriers {
+		if carrier.CanRead(user, token, &cr) {
+			readable.Carriers = append(readable.Carriers, cr)
+		}
+	}
+
+	return construction.Summarize(&readable)
+}
+
+// PUT /construction/connector/depot -> ColonisationConstructionDepot journal event
+func updateDepot(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	dto := depotDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	report := construction.DepotReport{
+		MarketID:  dto.MarketID,
+		System:    dto.System,
+		Name:      dto.StationName,
+		Progress:  dto.ConstructionProgress,
+		Complete:  dto.ConstructionComplete,
+		Failed:    dto.ConstructionFailed,
+		Resources: []construction.Resource{},
+	}
+
+	for _, resource := range dto.ResourcesRequired {
+		report.Resources = append(report.Resources, construction.Resource{
+			Commodity:      resource.Name,
+			Label:          resource.NameLocalised,
+			RequiredAmount: resource.RequiredAmount,
+			ProvidedAmount: resource.ProvidedAmount,
+		})
+	}
+
+	site, err := construction.ApplyDepotReport(&report, user)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{Full: true}, *site)
+}
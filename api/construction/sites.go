@@ -0,0 +1,294 @@
+package construction
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/construction"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /construction -> all sites, ?active=true hides completed and failed ones
+func getSites(c *gin.Context) {
+	query := db.DB.Order("created_at DESC")
+	if c.Query("active") == "true" {
+		query = query.Where("is_complete = ? AND is_failed = ?", false, false)
+	}
+
+	sites := []entities.ConstructionSite{}
+	if res := query.Find(&sites); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSONArray[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{}, sites)
+}
+
+// POST /construction -> creates a site manually
+func createSite(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	dto := createSiteDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.MarketID != nil {
+		if res := db.DB.Where("market_id = ?", *dto.MarketID).Limit(1).Find(&entities.ConstructionSite{}); res.RowsAffected > 0 {
+			errors.ReturnWithError(c, construction.ErrSiteAlreadyExists)
+			return
+		}
+	}
+
+	site := entities.ConstructionSite{
+		MarketID:    dto.MarketID,
+		System:      dto.System,
+		Name:        dto.Name,
+		CreatedByID: &user.ID,
+	}
+
+	if res := db.DB.Create(&site); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	if err := construction.SetRequirements(&site, resourcesFromDto(dto.Requirements)); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{Full: true}, site)
+}
+
+// GET /construction/:id
+func getSite(c *gin.Context) {
+	site, err := findSiteByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{Full: true}, *site)
+}
+
+// PATCH /construction/:id
+func updateSite(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	site, err := findSiteByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canManageSite(user, site) {
+		errors.ReturnWithError(c, construction.ErrForbidden)
+		return
+	}
+
+	dto := updateSiteDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.MarketID != nil {
+		if res := db.DB.Where("market_id = ? AND id != ?", *dto.MarketID, site.ID).Limit(1).Find(&entities.ConstructionSite{}); res.RowsAffected > 0 {
+			errors.ReturnWithError(c, construction.ErrSiteAlreadyExists)
+			return
+		}
+		site.MarketID = dto.MarketID
+	}
+
+	if dto.System != nil {
+		site.System = *dto.System
+	}
+
+	if dto.Name != nil {
+		site.Name = *dto.Name
+	}
+
+	if dto.Progress != nil {
+		site.Progress = *dto.Progress
+	}
+
+	if dto.IsComplete != nil {
+		site.IsComplete = *dto.IsComplete
+	}
+
+	if dto.IsFailed != nil {
+		site.IsFailed = *dto.IsFailed
+	}
+
+	if res := db.DB.Omit("Requirements", "Carriers").Save(site); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSON[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{Full: true}, *site)
+}
+
+// DELETE /construction/:id
+func deleteSite(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	site, err := findSiteByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canManageSite(user, site) {
+		errors.ReturnWithError(c, construction.ErrForbidden)
+		return
+	}
+
+	if err := db.DB.Model(site).Association("Carriers").Clear(); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	if res := db.DB.Unscoped().Select("Requirements").Delete(site); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, construction.ErrInternalServerError)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// PUT /construction/:id/requirements -> replaces the requirements manually
+func setSiteRequirements(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	site, err := findSiteByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canManageSite(user, site) {
+		errors.ReturnWithError(c, construction.ErrForbidden)
+		return
+	}
+
+	dto := setRequirementsDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if err := construction.SetRequirements(site, resourcesFromDto(dto.Requirements)); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.ConstructionSite](c, &serialize.ConstructionSiteSerializer{Full: true}, *site)
+}
+
+// PUT /construction/:id/carriers/:carrierId -> links a carrier ferrying materials to the site
+func linkCarrier(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	site, cr, err := findSiteAndCarrier(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	// linking puts the carrier's cargo into the site summary, so the carrier has to agree as well
+	if !carrier.CanWrite(user, auth.ApiTokenFromContext(c), cr) {
+		errors.ReturnWithError(c, construction.ErrForbidden)
+		return
+	}
+
+	if err := construction.LinkCarrier(site, cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// DELETE /construction/:id/carriers/:carrierId
+func unlinkCarrier(c *gin.Context) {
+	user := c.MustGet("user").(*entities.User)
+
+	site, cr, err := findSiteAndCarrier(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !canManageSite(user, site) && !carrier.IsOwner(user, cr) {
+		errors.ReturnWithError(c, construction.ErrForbidden)
+		return
+	}
+
+	if err := construction.UnlinkCarrier(site, cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+func canManageSite(user *entities.User, site *entities.ConstructionSite) bool {
+	return user.IsAdmin || (site.CreatedByID != nil && *site.CreatedByID == user.ID)
+}
+
+// Loads the site referenced by the :id route param.
+func findSiteByParam(c *gin.Context) (*entities.ConstructionSite, *errors.RstError) {
+	siteId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, construction.ErrInvalidSiteId
+	}
+
+	return construction.FindSite(siteId)
+}
+
+func findSiteAndCarrier(c *gin.Context) (*entities.ConstructionSite, *entities.Carrier, *errors.RstError) {
+	site, err := findSiteByParam(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	carrierId, parseErr := uuid.Parse(c.Param("carrierId"))
+	if parseErr != nil {
+		return nil, nil, construction.ErrInvalidCarrierId
+	}
+
+	cr := &entities.Carrier{}
+	if res := db.DB.Where("id = ?", carrierId).First(cr); res.Error != nil {
+		return nil, nil, construction.ErrCarrierNotFound
+	}
+
+	return site, cr, nil
+}
+
+func resourcesFromDto(requirements []resourceDto) []construction.Resource {
+	resources := make([]construction.Resource, len(requirements))
+	for i, requirement := range requirements {
+		resources[i] = construction.Resource{
+			Commodity:      requirement.Commodity,
+			Label:          requirement.Label,
+			RequiredAmount: requirement.RequiredAmount,
+			ProvidedAmount: requirement.ProvidedAmount,
+		}
+	}
+	return resources
+}
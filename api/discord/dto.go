@@ -0,0 +1,74 @@
+package discord
+
+import "ruehrstaat-backend/auth/discord"
+
+// Interaction types sent by Discord
+const (
+	interactionTypePing               = 1
+	interactionTypeApplicationCommand = 2
+)
+
+// Interaction callback types we respond with
+const (
+	callbackTypePong                     = 1
+	callbackTypeChannelMessageWithSource = 4
+)
+
+// Message flag that only shows the response to the invoking user
+const messageFlagEphemeral = 64
+
+type interactionDto struct {
+	ID     string                `json:"id"`
+	Type   int                   `json:"type"`
+	Data   *interactionDataDto   `json:"data"`
+	Member *interactionMemberDto `json:"member"`
+	User   *discord.DiscordUser  `json:"user"`
+}
+
+type interactionMemberDto struct {
+	User *discord.DiscordUser `json:"user"`
+}
+
+type interactionDataDto struct {
+	Name    string                 `json:"name"`
+	Options []interactionOptionDto `json:"options"`
+}
+
+type interactionOptionDto struct {
+	Name    string                 `json:"name"`
+	Type    int                    `json:"type"`
+	Value   interface{}            `json:"value"`
+	Options []interactionOptionDto `json:"options"`
+}
+
+// Returns the discord user that invoked the interaction, either from the guild member or the DM user.
+func (i *interactionDto) invoker() *discord.DiscordUser {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+type interactionResponseDto struct {
+	Type int                         `json:"type"`
+	Data *interactionResponseDataDto `json:"data,omitempty"`
+}
+
+type interactionResponseDataDto struct {
+	Content string     `json:"content,omitempty"`
+	Embeds  []embedDto `json:"embeds,omitempty"`
+	Flags   int        `json:"flags,omitempty"`
+}
+
+type embedDto struct {
+	Title       string          `json:"title,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Color       int             `json:"color,omitempty"`
+	Fields      []embedFieldDto `json:"fields,omitempty"`
+}
+
+type embedFieldDto struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
@@ -0,0 +1,22 @@
+package discord
+
+import "ruehrstaat-backend/errors"
+
+var ErrPackageDiscord = errors.NewPackage("Discord", "DC")
+
+// codes
+// 1xxx - invalid something
+// 2xxx - not found
+// 3xxx - already done / exists
+// 4xxx - forbidden
+// 5xxx - server error
+
+// 9xxx - other
+// 9999 - unknown error
+
+var (
+	ErrInvalidInteraction     = errors.New(1001, *ErrPackageDiscord, 400, "", "Invalid interaction")
+	ErrUnsupportedInteraction = errors.New(1002, *ErrPackageDiscord, 400, "", "Unsupported interaction type")
+
+	ErrInvalidSignature = errors.New(4001, *ErrPackageDiscord, 401, "", "Invalid request signature")
+)
@@ -0,0 +1,42 @@
+package discord
+
+import (
+	"bytes"
+	"io"
+	"ruehrstaat-backend/auth/discord"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/discord"}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+	discordApi := api.Group("/discord")
+
+	discordApi.POST("/interactions", interactionSignatureMiddleware(), handleInteraction)
+}
+
+// Rejects every request that is not signed by Discord. Discord itself probes the endpoint
+// with invalid signatures and expects a 401 in that case.
+func interactionSignatureMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(err)
+			errors.MiddlewareAbortWithError(c, ErrInvalidInteraction)
+			return
+		}
+
+		signature := c.GetHeader("X-Signature-Ed25519")
+		timestamp := c.GetHeader("X-Signature-Timestamp")
+		if !discord.VerifyInteraction(signature, timestamp, body) {
+			errors.MiddlewareAbortWithError(c, ErrInvalidSignature)
+			return
+		}
+
+		// restore body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+}
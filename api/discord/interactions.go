@@ -0,0 +1,232 @@
+package discord
+
+import (
+	"fmt"
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Discord allows at most 25 fields per embed
+const maxEmbedFields = 25
+
+const embedColor = 0xf0a30a
+
+func handleInteraction(c *gin.Context) {
+	dto := interactionDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	switch dto.Type {
+	case interactionTypePing:
+		c.JSON(200, interactionResponseDto{Type: callbackTypePong})
+	case interactionTypeApplicationCommand:
+		if dto.Data == nil || dto.Data.Name != "carrier" || len(dto.Data.Options) == 0 {
+			errors.ReturnWithError(c, ErrInvalidInteraction)
+			return
+		}
+
+		c.JSON(200, interactionResponseDto{
+			Type: callbackTypeChannelMessageWithSource,
+			Data: handleCarrierCommand(&dto, dto.Data.Options[0]),
+		})
+	default:
+		errors.ReturnWithError(c, ErrUnsupportedInteraction)
+	}
+}
+
+// Dispatches the `/carrier <subcommand>` command.
+func handleCarrierCommand(dto *interactionDto, subcommand interactionOptionDto) *interactionResponseDataDto {
+	switch subcommand.Name {
+	case "status":
+		return carrierStatusCommand(dto, optionString(subcommand.Options, "callsign"))
+	case "nearby":
+		return carrierNearbyCommand(optionString(subcommand.Options, "system"))
+	case "list":
+		return carrierListCommand()
+	default:
+		return ephemeralMessage("Unknown command")
+	}
+}
+
+func carrierStatusCommand(dto *interactionDto, callsign string) *interactionResponseDataDto {
+	if callsign == "" {
+		return ephemeralMessage("Please provide a callsign")
+	}
+
+	cr := entities.Carrier{}
+	if res := db.DB.Where("UPPER(callsign) = ?", strings.ToUpper(callsign)).Preload("Owner").First(&cr); res.Error != nil {
+		return ephemeralMessage(fmt.Sprintf("No carrier with callsign `%s` found", callsign))
+	}
+
+	serializer := &serialize.CarrierSerializer{Limited: true, Full: false}
+
+	// private data is only resolved for linked users that are allowed to see it
+	user := resolveLinkedUser(dto)
+	private := user != nil && (user.IsAdmin || (cr.OwnerID != nil && *cr.OwnerID == user.ID))
+	if private {
+		serializer = &serialize.CarrierSerializer{Limited: false, Full: true}
+	}
+
+	data := &interactionResponseDataDto{
+		Embeds: []embedDto{carrierEmbed(serialize.Do[entities.Carrier](serializer, cr).(*serialize.JsonObj), private)},
+	}
+
+	// do not leak private data into the channel
+	if private {
+		data.Flags = messageFlagEphemeral
+	}
+
+	return data
+}
+
+// Lists carriers that are currently located in the given system. We do not track system coordinates,
+// so "nearby" is limited to carriers in exactly that system.
+func carrierNearbyCommand(system string) *interactionResponseDataDto {
+	if system == "" {
+		return ephemeralMessage("Please provide a system name")
+	}
+
+	carriers := []entities.Carrier{}
+	if res := db.DB.Where("LOWER(current_location) = ?", strings.ToLower(system)).Preload("Owner").Find(&carriers); res.Error != nil {
+		log.Println("Failed to query carriers:", res.Error)
+		return ephemeralMessage("Could not load carriers, please try again later")
+	}
+
+	if len(carriers) == 0 {
+		return ephemeralMessage(fmt.Sprintf("No carriers in `%s`", system))
+	}
+
+	return &interactionResponseDataDto{
+		Embeds: []embedDto{carrierListEmbed("Carriers in "+system, carriers)},
+	}
+}
+
+func carrierListCommand() *interactionResponseDataDto {
+	carriers := []entities.Carrier{}
+	if res := db.DB.Preload("Owner").Order("name").Find(&carriers); res.Error != nil {
+		log.Println("Failed to query carriers:", res.Error)
+		return ephemeralMessage("Could not load carriers, please try again later")
+	}
+
+	if len(carriers) == 0 {
+		return ephemeralMessage("No carriers registered")
+	}
+
+	return &interactionResponseDataDto{
+		Embeds: []embedDto{carrierListEmbed("Carriers", carriers)},
+	}
+}
+
+// Looks up the user whose linked discord account invoked the interaction.
+func resolveLinkedUser(dto *interactionDto) *entities.User {
+	invoker := dto.invoker()
+	if invoker == nil || invoker.ID == "" {
+		return nil
+	}
+
+	user := &entities.User{}
+	if res := db.DB.Where("discord_id = ?", invoker.ID).First(user); res.Error != nil {
+		return nil
+	}
+
+	if user.IsBanned {
+		return nil
+	}
+
+	return user
+}
+
+func carrierEmbed(obj *serialize.JsonObj, private bool) embedDto {
+	embed := embedDto{
+		Title: fmt.Sprintf("%v (%v)", (*obj)["name"], (*obj)["callsign"]),
+		Color: embedColor,
+		Fields: []embedFieldDto{
+			{Name: "Location", Value: fieldValue(obj, "currentLocation"), Inline: true},
+			{Name: "Docking Access", Value: fieldValue(obj, "dockingAccess"), Inline: true},
+			{Name: "Category", Value: fieldValue(obj, "category"), Inline: true},
+			{Name: "Owner", Value: fieldValue(obj, "owner"), Inline: true},
+			{Name: "Services", Value: servicesValue(obj), Inline: false},
+		},
+	}
+
+	if private {
+		embed.Fields = append(embed.Fields,
+			embedFieldDto{Name: "Fuel", Value: fieldValue(obj, "fuelLevel") + " / 1000", Inline: true},
+			embedFieldDto{Name: "Cargo", Value: fieldValue(obj, "cargoUsed") + " / " + fieldValue(obj, "cargoSpace"), Inline: true},
+			embedFieldDto{Name: "Balance", Value: fieldValue(obj, "balance") + " CR", Inline: true},
+		)
+	}
+
+	return embed
+}
+
+func carrierListEmbed(title string, carriers []entities.Carrier) embedDto {
+	serialized := serialize.DoArray[entities.Carrier](&serialize.CarrierSerializer{Limited: true, Full: false}, carriers)
+
+	embed := embedDto{Title: title, Color: embedColor}
+	for i, s := range serialized {
+		if i == maxEmbedFields {
+			embed.Description = fmt.Sprintf("Showing %d of %d carriers", maxEmbedFields, len(carriers))
+			break
+		}
+
+		obj := s.(*serialize.JsonObj)
+		embed.Fields = append(embed.Fields, embedFieldDto{
+			Name:   fmt.Sprintf("%v (%v)", (*obj)["name"], (*obj)["callsign"]),
+			Value:  fieldValue(obj, "currentLocation") + " · " + fieldValue(obj, "dockingAccess"),
+			Inline: false,
+		})
+	}
+
+	return embed
+}
+
+func fieldValue(obj *serialize.JsonObj, key string) string {
+	value, ok := (*obj)[key]
+	if !ok || value == nil {
+		return "-"
+	}
+
+	str := fmt.Sprint(value)
+	if str == "" {
+		return "-"
+	}
+	return str
+}
+
+func servicesValue(obj *serialize.JsonObj) string {
+	services, ok := (*obj)["services"].([]interface{})
+	if !ok || len(services) == 0 {
+		return "-"
+	}
+
+	labels := []string{}
+	for _, service := range services {
+		labels = append(labels, fieldValue(service.(*serialize.JsonObj), "label"))
+	}
+	return strings.Join(labels, ", ")
+}
+
+func optionString(options []interactionOptionDto, name string) string {
+	for _, option := range options {
+		if option.Name == name {
+			if value, ok := option.Value.(string); ok {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+func ephemeralMessage(content string) *interactionResponseDataDto {
+	return &interactionResponseDataDto{Content: content, Flags: messageFlagEphemeral}
+}
@@ -2,7 +2,14 @@ package api
 
 import (
 	"ruehrstaat-backend/api/auth"
+	"ruehrstaat-backend/api/calendar"
 	"ruehrstaat-backend/api/carrier"
+	"ruehrstaat-backend/api/construction"
+	"ruehrstaat-backend/api/discord"
+	"ruehrstaat-backend/api/infra"
+	"ruehrstaat-backend/api/logistics"
+	"ruehrstaat-backend/api/oauth"
+	"ruehrstaat-backend/api/operations"
 	"ruehrstaat-backend/api/public"
 	"ruehrstaat-backend/api/users"
 
@@ -16,4 +23,13 @@ func RegisterRoutes(router *gin.RouterGroup) {
 	users.RegisterRoutes(api)
 	public.RegisterRoutes(api)
 	carrier.RegisterRoutes(api)
+	discord.RegisterRoutes(api)
+	calendar.RegisterRoutes(api)
+	construction.RegisterRoutes(api)
+	logistics.RegisterRoutes(api)
+	operations.RegisterRoutes(api)
+	infra.RegisterRoutes(api)
+	oauth.RegisterRoutes(api)
+
+	oauth.RegisterWellKnownRoutes(router)
 }
@@ -0,0 +1,220 @@
+package infra
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const maxSecretGracePeriod = time.Hour * 24 * 7
+
+func findClient(c *gin.Context) (*entities.InfraToken, bool) {
+	if _, err := uuid.Parse(c.Param("id")); err != nil {
+		errors.ReturnWithError(c, auth.ErrInvalidUUID)
+		return nil, false
+	}
+
+	client, err := auth.GetInfraClient(c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return nil, false
+	}
+	return client, true
+}
+
+// GET /infra/clients
+func getClients(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	clients := []entities.InfraToken{}
+	if res := db.DB.Order("name").Find(&clients); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+
+	serialize.JSONArray[entities.InfraToken](c, &serialize.InfraClientSerializer{}, clients)
+}
+
+// POST /infra/clients -> the secret is only returned once
+func createClient(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	dto := createClientDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	client := &entities.InfraToken{
+		Name:           dto.Name,
+		Scopes:         pq.StringArray(dto.Scopes),
+		RedirectURIs:   pq.StringArray(dto.RedirectURIs),
+		OAuthScopes:    pq.StringArray(dto.OAuthScopes),
+		IsPublicClient: dto.IsPublicClient,
+	}
+	if client.RedirectURIs == nil {
+		client.RedirectURIs = pq.StringArray{}
+	}
+	if client.OAuthScopes == nil {
+		client.OAuthScopes = pq.StringArray{}
+	}
+
+	secret, err := auth.RegisterInfraClient(client)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"client": serialize.Do[entities.InfraToken](&serialize.InfraClientSerializer{}, *client),
+		"secret": secret,
+	})
+}
+
+// GET /infra/clients/:id
+func getClient(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	client, ok := findClient(c)
+	if !ok {
+		return
+	}
+
+	serialize.JSON[entities.InfraToken](c, &serialize.InfraClientSerializer{}, *client)
+}
+
+// PATCH /infra/clients/:id -> renames the client, replaces its scopes or (un)revokes it
+func updateClient(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	client, ok := findClient(c)
+	if !ok {
+		return
+	}
+
+	dto := updateClientDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.Name != nil {
+		name := strings.TrimSpace(*dto.Name)
+		var count int64
+		if res := db.DB.Model(&entities.InfraToken{}).Where("name = ? AND id != ?", name, client.ID).Count(&count); res.Error != nil {
+			errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+			return
+		}
+		if count > 0 {
+			errors.ReturnWithError(c, auth.ErrInfraClientNameTaken)
+			return
+		}
+		client.Name = name
+	}
+
+	if dto.Scopes != nil {
+		client.Scopes = pq.StringArray(*dto.Scopes)
+	}
+	if dto.IsRevoked != nil {
+		client.IsRevoked = *dto.IsRevoked
+	}
+	if dto.RedirectURIs != nil {
+		client.RedirectURIs = pq.StringArray(*dto.RedirectURIs)
+	}
+	if dto.OAuthScopes != nil {
+		client.OAuthScopes = pq.StringArray(*dto.OAuthScopes)
+	}
+	if dto.IsPublicClient != nil {
+		client.IsPublicClient = *dto.IsPublicClient
+	}
+
+	if err := auth.ValidateInfraClient(client); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if res := db.DB.Model(client).Select("name", "scopes", "is_revoked", "redirect_uris", "oauth_scopes", "is_public_client").Updates(client); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+
+	serialize.JSON[entities.InfraToken](c, &serialize.InfraClientSerializer{}, *client)
+}
+
+// DELETE /infra/clients/:id
+func deleteClient(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	client, ok := findClient(c)
+	if !ok {
+		return
+	}
+
+	if res := db.DB.Delete(client); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// POST /infra/clients/:id/rotate -> issues a new secret, the previous one stays valid for the grace period
+func rotateClientSecret(c *gin.Context) {
+	if _, authorized := auth.AutoAuthorizeAdmin(c); !authorized {
+		return
+	}
+
+	client, ok := findClient(c)
+	if !ok {
+		return
+	}
+
+	dto := rotateSecretDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	var grace time.Duration
+	if dto.GracePeriod != "" {
+		parsed, err := time.ParseDuration(dto.GracePeriod)
+		if err != nil || parsed < 0 || parsed > maxSecretGracePeriod {
+			errors.ReturnWithError(c, dtoerr.InvalidDTO)
+			return
+		}
+		grace = parsed
+	}
+
+	secret, err := auth.RotateInfraSecret(client, grace)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"client": serialize.Do[entities.InfraToken](&serialize.InfraClientSerializer{}, *client),
+		"secret": secret,
+	})
+}
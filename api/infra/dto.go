@@ -0,0 +1,35 @@
+package infra
+
+import "github.com/google/uuid"
+
+type createClientDto struct {
+	Name   string   `json:"name" binding:"required,max=255"`
+	Scopes []string `json:"scopes" binding:"required"`
+
+	RedirectURIs   []string `json:"redirectUris"`
+	OAuthScopes    []string `json:"oauthScopes"`
+	IsPublicClient bool     `json:"isPublicClient"`
+}
+
+type updateClientDto struct {
+	Name      *string   `json:"name" binding:"omitempty,max=255"`
+	Scopes    *[]string `json:"scopes"`
+	IsRevoked *bool     `json:"isRevoked"`
+
+	RedirectURIs   *[]string `json:"redirectUris"`
+	OAuthScopes    *[]string `json:"oauthScopes"`
+	IsPublicClient *bool     `json:"isPublicClient"`
+}
+
+type rotateSecretDto struct {
+	// How long the previous secret stays valid, e.g. "24h". Empty invalidates it immediately.
+	GracePeriod string `json:"gracePeriod"`
+}
+
+type notificationDto struct {
+	UserIDs    []uuid.UUID `json:"userIds"`
+	DiscordIDs []string    `json:"discordIds"`
+	Subject    string      `json:"subject" binding:"required,max=255"`
+	Message    string      `json:"message" binding:"required,max=5000"`
+	Link       string      `json:"link" binding:"omitempty,url"`
+}
@@ -0,0 +1,44 @@
+package infra
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/infra"}
+
+// Routes for service-to-service access, e.g. by the Discord bot or the website backend.
+// Clients authenticate with the X-RST-Client-Id and X-RST-Client-Secret headers.
+func RegisterRoutes(api *gin.RouterGroup) {
+	infraApi := api.Group("/infra")
+
+	clientApi := infraApi.Group("/", infraMiddleware())
+	clientApi.GET("/users/discord/:discordId", auth.RequireInfraScope(entities.InfraScopeUsersRead), getUserByDiscordId)
+	clientApi.GET("/carriers", auth.RequireInfraScope(entities.InfraScopeCarriersRead), getCarriers)
+	clientApi.POST("/notifications", auth.RequireInfraScope(entities.InfraScopeNotificationsSend), sendNotification)
+
+	// managed by admins with their regular session
+	adminGroup := infraApi.Group("/clients")
+	adminGroup.GET("/", getClients)
+	adminGroup.POST("/", createClient)
+	adminGroup.GET("/:id", getClient)
+	adminGroup.PATCH("/:id", updateClient)
+	adminGroup.DELETE("/:id", deleteClient)
+	adminGroup.POST("/:id/rotate", rotateClientSecret)
+}
+
+func infraMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		infra := auth.AuthenticateInfra(c)
+		if infra == nil {
+			errors.MiddlewareAbortWithError(c, auth.ErrUnauthorized)
+			return
+		}
+
+		c.Set("infra", infra)
+	}
+}
@@ -0,0 +1,38 @@
+package infra
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /infra/users/discord/:discordId -> user that linked the Discord account
+func getUserByDiscordId(c *gin.Context) {
+	user := entities.User{}
+	res := db.DB.Where("discord_id = ?", c.Param("discordId")).Limit(1).Find(&user)
+	if res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+	if res.RowsAffected == 0 {
+		errors.ReturnWithError(c, auth.ErrUserNotFound)
+		return
+	}
+
+	serialize.JSON[entities.User](c, &serialize.UserSerializer{Full: true}, user)
+}
+
+// GET /infra/carriers
+func getCarriers(c *gin.Context) {
+	carriers := []entities.Carrier{}
+	if res := db.DB.Preload("Owner").Order("name").Find(&carriers); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+
+	serialize.JSONArray[entities.Carrier](c, (&serialize.CarrierSerializer{}).ParseFlags(c), carriers)
+}
@@ -0,0 +1,61 @@
+package infra
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/mailer"
+	"ruehrstaat-backend/mailer/mails"
+
+	"github.com/gin-gonic/gin"
+)
+
+// POST /infra/notifications -> emails the given users, addressed by user or Discord id
+func sendNotification(c *gin.Context) {
+	client := auth.InfraFromContext(c)
+
+	dto := notificationDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if len(dto.UserIDs) == 0 && len(dto.DiscordIDs) == 0 {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	query := db.DB.Where("is_activated = ? AND is_banned = ?", true, false)
+	if len(dto.UserIDs) > 0 && len(dto.DiscordIDs) > 0 {
+		query = query.Where("id IN ? OR discord_id IN ?", dto.UserIDs, dto.DiscordIDs)
+	} else if len(dto.UserIDs) > 0 {
+		query = query.Where("id IN ?", dto.UserIDs)
+	} else {
+		query = query.Where("discord_id IN ?", dto.DiscordIDs)
+	}
+
+	users := []entities.User{}
+	if res := query.Find(&users); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+
+	go func() {
+		for _, user := range users {
+			err := mailer.SendMailGraceful(user.Email, mails.NotificationMail{
+				Nickname: user.Nickname,
+				Subject:  dto.Subject,
+				Message:  dto.Message,
+				Link:     dto.Link,
+			}, user.Locale)
+			if err != nil {
+				log.Println("Failed to send notification of infra client", client.Name, "to", user.ID, err)
+			}
+		}
+	}()
+
+	c.JSON(202, gin.H{"recipients": len(users)})
+}
@@ -0,0 +1,26 @@
+package logistics
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type createRequestDto struct {
+	Type              string     `json:"type" binding:"required"` // haul, jump
+	Commodity         string     `json:"commodity"`
+	Quantity          int        `json:"quantity"`
+	DestinationSystem string     `json:"destinationSystem" binding:"required"`
+	Deadline          *time.Time `json:"deadline"`
+	Note              string     `json:"note"`
+	// Optional carrier the request is addressed to
+	CarrierID *uuid.UUID `json:"carrierId"`
+}
+
+type claimRequestDto struct {
+	CarrierID uuid.UUID `json:"carrierId" binding:"required"`
+}
+
+type respondRequestDto struct {
+	Response string `json:"response"`
+}
@@ -0,0 +1,22 @@
+package logistics
+
+import (
+	"ruehrstaat-backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/logistics"}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+	logisticsApi := api.Group("/logistics")
+
+	logisticsApi.GET("/", getOpenRequests)
+	logisticsApi.POST("/", createRequest)
+	logisticsApi.GET("/:id", getRequest)
+	logisticsApi.POST("/:id/claim", claimRequest)
+	logisticsApi.POST("/:id/accept", acceptRequest)
+	logisticsApi.POST("/:id/decline", declineRequest)
+	logisticsApi.POST("/:id/complete", completeRequest)
+	logisticsApi.POST("/:id/cancel", cancelRequest)
+}
@@ -0,0 +1,201 @@
+package logistics
+
+import (
+	"io"
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/logistics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /logistics -> open requests the current user could pick up
+func getOpenRequests(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	requests, err := logistics.ListOpen(current)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, requests)
+}
+
+// POST /logistics -> files a new request
+func createRequest(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	dto := createRequestDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	request := entities.LogisticsRequest{
+		Type:              entities.LogisticsRequestType(dto.Type),
+		Commodity:         dto.Commodity,
+		Quantity:          dto.Quantity,
+		DestinationSystem: dto.DestinationSystem,
+		Deadline:          dto.Deadline,
+		Note:              dto.Note,
+	}
+
+	if dto.CarrierID != nil {
+		cr := &entities.Carrier{}
+		if res := db.DB.Where("id = ?", dto.CarrierID).First(cr); res.Error != nil {
+			errors.ReturnWithError(c, logistics.ErrCarrierNotFound)
+			return
+		}
+		request.CarrierID = &cr.ID
+		request.Carrier = cr
+	}
+
+	if err := logistics.Create(current, &request); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, request)
+}
+
+// GET /logistics/:id
+func getRequest(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	request, err := findRequestByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !logistics.CanView(current, request) {
+		errors.ReturnWithError(c, logistics.ErrForbidden)
+		return
+	}
+
+	serialize.JSON[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, *request)
+}
+
+// POST /logistics/:id/claim -> assigns the request to one of the current user's carriers
+func claimRequest(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	request, err := findRequestByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	dto := claimRequestDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	cr := &entities.Carrier{}
+	if res := db.DB.Where("id = ?", dto.CarrierID).First(cr); res.Error != nil {
+		errors.ReturnWithError(c, logistics.ErrCarrierNotFound)
+		return
+	}
+
+	if err := logistics.Claim(request, cr, current); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, *request)
+}
+
+// POST /logistics/:id/accept
+func acceptRequest(c *gin.Context) {
+	respondToRequest(c, logistics.Accept)
+}
+
+// POST /logistics/:id/decline
+func declineRequest(c *gin.Context) {
+	respondToRequest(c, logistics.Decline)
+}
+
+// POST /logistics/:id/complete
+func completeRequest(c *gin.Context) {
+	respondToRequest(c, logistics.Complete)
+}
+
+func respondToRequest(c *gin.Context, respond func(*entities.LogisticsRequest, *entities.User, string) *errors.RstError) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	request, err := findRequestByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	// the response message is optional, so is the body
+	dto := respondRequestDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil && err != io.EOF {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if err := respond(request, current, dto.Response); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, *request)
+}
+
+// POST /logistics/:id/cancel -> withdraws the request, only for the requester
+func cancelRequest(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	request, err := findRequestByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if err := logistics.Cancel(request, current); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, *request)
+}
+
+// Loads the request referenced by the :id route param.
+func findRequestByParam(c *gin.Context) (*entities.LogisticsRequest, *errors.RstError) {
+	requestId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, logistics.ErrInvalidRequestId
+	}
+
+	return logistics.Find(requestId)
+}
@@ -0,0 +1,127 @@
+package oauth
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (q *authorizeQuery) toRequest() *auth.AuthorizationRequest {
+	return &auth.AuthorizationRequest{
+		ResponseType:        q.ResponseType,
+		ClientID:            q.ClientID,
+		RedirectURI:         q.RedirectURI,
+		Scope:               q.Scope,
+		State:               q.State,
+		CodeChallenge:       q.CodeChallenge,
+		CodeChallengeMethod: q.CodeChallengeMethod,
+		Nonce:               q.Nonce,
+	}
+}
+
+// Errors about the client or redirect uri are shown to the user, everything else is reported
+// back to the client through the redirect uri.
+func returnAuthorizationError(c *gin.Context, q *authorizeQuery, err *errors.RstError) {
+	if err == auth.ErrInvalidClient || err == auth.ErrInvalidRedirectUri {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.Error(err)
+	c.JSON(err.HtmlCode(), gin.H{
+		"error": err.Message(),
+		"code":  err.Code(),
+		"name":  err.Nickname(),
+		"redirectTo": auth.BuildOAuthRedirect(q.RedirectURI, map[string]string{
+			"error": err.Nickname(),
+			"state": q.State,
+		}),
+	})
+}
+
+// GET /oauth/authorize -> data for the consent screen
+func getAuthorization(c *gin.Context) {
+	user, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	query := authorizeQuery{}
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	client, scopes, err := auth.ValidateAuthorizationRequest(query.toRequest())
+	if err != nil {
+		returnAuthorizationError(c, &query, err)
+		return
+	}
+
+	// the frontend may skip the consent screen if the user already granted these scopes
+	grants := []entities.OAuthGrant{}
+	if res := db.DB.Where("client_id = ? AND user_id = ?", client.ID, user.ID).Find(&grants); res.Error != nil {
+		errors.ReturnWithError(c, errors.NewDBErrorFromError(res.Error))
+		return
+	}
+	previouslyGranted := false
+	for _, grant := range grants {
+		if grant.IsActive() && auth.IsScopeSubset(scopes, grant.Scopes) {
+			previouslyGranted = true
+			break
+		}
+	}
+
+	c.JSON(200, gin.H{
+		"client": gin.H{
+			"id":   client.ID,
+			"name": client.Name,
+		},
+		"scopes":            scopes,
+		"redirectUri":       query.RedirectURI,
+		"previouslyGranted": previouslyGranted,
+	})
+}
+
+// POST /oauth/authorize -> records the decision of the user, the frontend redirects to redirectTo
+func authorize(c *gin.Context) {
+	user, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	body := authorizeBody{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	req := body.toRequest()
+	if !body.Approve {
+		if _, _, err := auth.ValidateAuthorizationRequest(req); err != nil {
+			returnAuthorizationError(c, &body.authorizeQuery, err)
+			return
+		}
+
+		c.JSON(200, gin.H{"redirectTo": auth.BuildOAuthRedirect(req.RedirectURI, map[string]string{
+			"error": "access_denied",
+			"state": req.State,
+		})})
+		return
+	}
+
+	code, err := auth.AuthorizeOAuthClient(req, user)
+	if err != nil {
+		returnAuthorizationError(c, &body.authorizeQuery, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"redirectTo": auth.BuildOAuthRedirect(req.RedirectURI, map[string]string{
+		"code":  code,
+		"state": req.State,
+	})})
+}
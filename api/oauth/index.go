@@ -0,0 +1,24 @@
+package oauth
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// "Log in with Ruehrstaat" for third party apps. The consent screen is part of the frontend,
+// which uses the authorize routes with the session of the user.
+func RegisterRoutes(api *gin.RouterGroup) {
+	oauthApi := api.Group("/oauth")
+
+	oauthApi.GET("/authorize", getAuthorization)
+	oauthApi.POST("/authorize", authorize)
+	oauthApi.POST("/token", token)
+	oauthApi.POST("/revoke", revoke)
+	oauthApi.GET("/userinfo", getUserInfo)
+	oauthApi.POST("/userinfo", getUserInfo)
+}
+
+// OpenID Connect discovery has to live below the issuer, which is the root of the backend.
+func RegisterWellKnownRoutes(router *gin.RouterGroup) {
+	router.GET("/.well-known/openid-configuration", getOpenIDConfiguration)
+	router.GET("/.well-known/jwks.json", getJWKS)
+}
@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"os"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /.well-known/openid-configuration
+func getOpenIDConfiguration(c *gin.Context) {
+	issuer := auth.OIDCIssuer()
+
+	scopes := []string{}
+	for _, scope := range entities.OIDCScopes {
+		scopes = append(scopes, string(scope))
+	}
+	for _, scope := range entities.ApiTokenScopes {
+		scopes = append(scopes, string(scope))
+	}
+
+	c.JSON(200, gin.H{
+		"issuer":                                issuer,
+		"authorization_endpoint":                os.Getenv("FRONTEND_URL") + "/oauth/authorize",
+		"token_endpoint":                        issuer + "/v1/oauth/token",
+		"userinfo_endpoint":                     issuer + "/v1/oauth/userinfo",
+		"revocation_endpoint":                   issuer + "/v1/oauth/revoke",
+		"jwks_uri":                              issuer + "/.well-known/jwks.json",
+		"scopes_supported":                      scopes,
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"claims_supported":                      []string{"iss", "sub", "aud", "exp", "iat", "nonce", "nickname", "cmdrName", "email", "email_verified"},
+	})
+}
+
+// GET /.well-known/jwks.json
+func getJWKS(c *gin.Context) {
+	c.JSON(200, gin.H{"keys": auth.SigningKeyJWKS()})
+}
+
+// GET /oauth/userinfo -> claims of the user the access token was issued for
+func getUserInfo(c *gin.Context) {
+	user, token := auth.AuthenticateApiTokenUser(c)
+	if token == nil {
+		return
+	}
+
+	if token.GrantID == nil || !token.HasScope(entities.ScopeOpenID) {
+		c.Header("WWW-Authenticate", `Bearer error="insufficient_scope"`)
+		errors.ReturnWithError(c, auth.ErrInsufficientScope)
+		return
+	}
+
+	c.JSON(200, auth.OIDCUserClaims(user, token.Scopes))
+}
@@ -0,0 +1,85 @@
+package oauth
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Client credentials are sent with basic auth or in the body.
+func authenticateClient(c *gin.Context, clientId string, clientSecret string) (*entities.InfraToken, *errors.RstError) {
+	if id, secret, ok := c.Request.BasicAuth(); ok {
+		clientId, clientSecret = id, secret
+	}
+
+	return auth.AuthenticateOAuthClient(clientId, clientSecret)
+}
+
+// POST /oauth/token
+func token(c *gin.Context) {
+	body := tokenBody{}
+	if err := c.ShouldBind(&body); err != nil {
+		errors.ReturnWithOAuthError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	client, err := authenticateClient(c, body.ClientID, body.ClientSecret)
+	if err != nil {
+		errors.ReturnWithOAuthError(c, err)
+		return
+	}
+
+	var tokens *auth.OAuthTokens
+	switch body.GrantType {
+	case "authorization_code":
+		tokens, err = auth.ExchangeOAuthCode(client, body.Code, body.RedirectURI, body.CodeVerifier)
+	case "refresh_token":
+		tokens, err = auth.RefreshOAuthGrant(client, body.RefreshToken, body.Scope)
+	default:
+		err = auth.ErrUnsupportedGrantType
+	}
+	if err != nil {
+		errors.ReturnWithOAuthError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"access_token":  tokens.AccessToken,
+		"token_type":    "Bearer",
+		"expires_in":    tokens.ExpiresIn,
+		"refresh_token": tokens.RefreshToken,
+		"scope":         strings.Join(tokens.Scopes, " "),
+	}
+	if tokens.IDToken != "" {
+		response["id_token"] = tokens.IDToken
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(200, response)
+}
+
+// POST /oauth/revoke
+func revoke(c *gin.Context) {
+	body := revokeBody{}
+	if err := c.ShouldBind(&body); err != nil {
+		errors.ReturnWithOAuthError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	client, err := authenticateClient(c, body.ClientID, body.ClientSecret)
+	if err != nil {
+		errors.ReturnWithOAuthError(c, err)
+		return
+	}
+
+	if err := auth.RevokeOAuthToken(client, body.Token); err != nil {
+		errors.ReturnWithOAuthError(c, err)
+		return
+	}
+
+	c.Status(200)
+}
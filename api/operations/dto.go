@@ -0,0 +1,28 @@
+package operations
+
+import "time"
+
+type createOperationDto struct {
+	Title         string    `json:"title" binding:"required"`
+	Description   string    `json:"description"`
+	Category      string    `json:"category"`
+	StartsAt      time.Time `json:"startsAt" binding:"required"`
+	EndsAt        time.Time `json:"endsAt" binding:"required"`
+	TargetSystems []string  `json:"targetSystems"`
+	IsPublic      bool      `json:"isPublic"`
+}
+
+type updateOperationDto struct {
+	Title         *string    `json:"title"`
+	Description   *string    `json:"description"`
+	Category      *string    `json:"category"`
+	StartsAt      *time.Time `json:"startsAt"`
+	EndsAt        *time.Time `json:"endsAt"`
+	TargetSystems *[]string  `json:"targetSystems"`
+	IsPublic      *bool      `json:"isPublic"`
+}
+
+type rsvpDto struct {
+	Status string `json:"status" binding:"required"` // going, maybe, declined
+	Note   string `json:"note" binding:"max=255"`
+}
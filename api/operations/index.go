@@ -0,0 +1,24 @@
+package operations
+
+import (
+	"ruehrstaat-backend/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+var log = logging.Logger{Package: "api/operations"}
+
+func RegisterRoutes(api *gin.RouterGroup) {
+	operationsApi := api.Group("/operations")
+
+	operationsApi.GET("/", getOperations)
+	operationsApi.POST("/", createOperation)
+	operationsApi.GET("/:id", getOperation)
+	operationsApi.PATCH("/:id", updateOperation)
+	operationsApi.DELETE("/:id", deleteOperation)
+	operationsApi.GET("/:id/timeline", getOperationTimeline)
+	operationsApi.PUT("/:id/carriers/:carrierId", assignCarrier)
+	operationsApi.DELETE("/:id/carriers/:carrierId", unassignCarrier)
+	operationsApi.PUT("/:id/rsvp", rsvpOperation)
+	operationsApi.DELETE("/:id/rsvp", removeRsvp)
+}
@@ -0,0 +1,347 @@
+package operations
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/operations"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /operations -> upcoming and running operations, ?past=true for ended ones
+func getOperations(c *gin.Context) {
+	_, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	list, err := operations.List(c.Query("past") == "true", false)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[entities.Operation](c, &serialize.OperationSerializer{}, list)
+}
+
+// POST /operations
+func createOperation(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	dto := createOperationDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	operation := entities.Operation{
+		Title:         dto.Title,
+		Description:   dto.Description,
+		Category:      entities.OperationCategory(dto.Category),
+		StartsAt:      dto.StartsAt,
+		EndsAt:        dto.EndsAt,
+		TargetSystems: dto.TargetSystems,
+		IsPublic:      dto.IsPublic,
+		CreatedByID:   &current.ID,
+	}
+
+	if operation.Category == "" {
+		operation.Category = entities.OperationCategoryOther
+	}
+	if operation.TargetSystems == nil {
+		operation.TargetSystems = []string{}
+	}
+
+	if err := operations.Validate(&operation); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if res := db.DB.Create(&operation); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, operations.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSON[entities.Operation](c, &serialize.OperationSerializer{Full: true}, operation)
+}
+
+// GET /operations/:id
+func getOperation(c *gin.Context) {
+	_, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.Operation](c, &serialize.OperationSerializer{Full: true}, *operation)
+}
+
+// PATCH /operations/:id
+func updateOperation(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !operations.CanManage(current, operation) {
+		errors.ReturnWithError(c, operations.ErrForbidden)
+		return
+	}
+
+	dto := updateOperationDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.Title != nil {
+		operation.Title = *dto.Title
+	}
+
+	if dto.Description != nil {
+		operation.Description = *dto.Description
+	}
+
+	if dto.Category != nil {
+		operation.Category = entities.OperationCategory(*dto.Category)
+	}
+
+	if dto.StartsAt != nil && !dto.StartsAt.Equal(operation.StartsAt) {
+		operation.StartsAt = *dto.StartsAt
+		// remind again for the new start
+		operation.ReminderSentAt = nil
+	}
+
+	if dto.EndsAt != nil {
+		operation.EndsAt = *dto.EndsAt
+	}
+
+	if dto.TargetSystems != nil {
+		operation.TargetSystems = *dto.TargetSystems
+	}
+
+	if dto.IsPublic != nil {
+		operation.IsPublic = *dto.IsPublic
+	}
+
+	if err := operations.Validate(operation); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if res := db.DB.Omit("Carriers", "Rsvps").Save(operation); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, operations.ErrInternalServerError)
+		return
+	}
+
+	serialize.JSON[entities.Operation](c, &serialize.OperationSerializer{Full: true}, *operation)
+}
+
+// DELETE /operations/:id
+func deleteOperation(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !operations.CanManage(current, operation) {
+		errors.ReturnWithError(c, operations.ErrForbidden)
+		return
+	}
+
+	if err := db.DB.Model(operation).Association("Carriers").Clear(); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, operations.ErrInternalServerError)
+		return
+	}
+
+	if res := db.DB.Select("Rsvps").Delete(operation); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, operations.ErrInternalServerError)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// GET /operations/:id/timeline -> time window of the operation merged with the scheduled jumps of its carriers
+func getOperationTimeline(c *gin.Context) {
+	_, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	timeline, err := operations.Timeline(operation)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[operations.TimelineEntry](c, &serialize.OperationTimelineSerializer{}, timeline)
+}
+
+// PUT /operations/:id/carriers/:carrierId
+func assignCarrier(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, cr, err := findOperationAndCarrier(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !operations.CanManage(current, operation) {
+		errors.ReturnWithError(c, operations.ErrForbidden)
+		return
+	}
+
+	if err := operations.AssignCarrier(operation, cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// DELETE /operations/:id/carriers/:carrierId
+func unassignCarrier(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, cr, err := findOperationAndCarrier(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !operations.CanManage(current, operation) {
+		errors.ReturnWithError(c, operations.ErrForbidden)
+		return
+	}
+
+	if err := operations.UnassignCarrier(operation, cr); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// PUT /operations/:id/rsvp -> RSVP of the current user
+func rsvpOperation(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	dto := rsvpDto{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	rsvp, err := operations.Rsvp(operation, current, entities.OperationRsvpStatus(dto.Status), dto.Note)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.OperationRsvp](c, &serialize.OperationRsvpSerializer{}, *rsvp)
+}
+
+// DELETE /operations/:id/rsvp
+func removeRsvp(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if err := operations.RemoveRsvp(operation, current); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// Loads the operation referenced by the :id route param.
+func findOperationByParam(c *gin.Context) (*entities.Operation, *errors.RstError) {
+	operationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, operations.ErrInvalidOperationId
+	}
+
+	return operations.Find(operationId)
+}
+
+func findOperationAndCarrier(c *gin.Context) (*entities.Operation, *entities.Carrier, *errors.RstError) {
+	operation, err := findOperationByParam(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	carrierId, parseErr := uuid.Parse(c.Param("carrierId"))
+	if parseErr != nil {
+		return nil, nil, operations.ErrInvalidCarrierId
+	}
+
+	cr := &entities.Carrier{}
+	if res := db.DB.Where("id = ?", carrierId).First(cr); res.Error != nil {
+		return nil, nil, operations.ErrCarrierNotFound
+	}
+
+	return operation, cr, nil
+}
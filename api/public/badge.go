@@ -0,0 +1,58 @@
+package public
+
+import (
+	"net/http"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/badge"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /public/carrier/:id/badge.svg
+func publicGetCarrierBadgeSVG(c *gin.Context) {
+	writeCarrierBadge(c, badge.FormatSVG, "image/svg+xml")
+}
+
+// GET /public/carrier/:id/badge.png
+func publicGetCarrierBadgePNG(c *gin.Context) {
+	writeCarrierBadge(c, badge.FormatPNG, "image/png")
+}
+
+func writeCarrierBadge(c *gin.Context, format string, contentType string) {
+	carrierId := c.Param("id")
+	if carrierId == "" {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	cr := entities.Carrier{}
+	if res := db.DB.Where("id = ? AND is_public = ?", carrierId, true).First(&cr); res.Error != nil {
+		errors.ReturnWithError(c, carrier.ErrCarrierNotFound)
+		return
+	}
+
+	theme := badge.GetTheme(c.Query("theme"))
+
+	etag := `"` + badge.CacheKey(&cr, format, theme) + `"`
+	lastModified := cr.UpdatedAt.UTC()
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	data, err := badge.Render(&cr, format, theme)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.Data(200, contentType, data)
+}
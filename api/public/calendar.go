@@ -0,0 +1,35 @@
+package public
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/calendar"
+	"ruehrstaat-backend/services/carrier"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /public/carrier/:id/jumps.ics
+func publicGetCarrierCalendar(c *gin.Context) {
+	carrierId := c.Param("id")
+	if carrierId == "" {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	cr := entities.Carrier{}
+	if res := db.DB.Where("id = ? AND is_public = ?", carrierId, true).First(&cr); res.Error != nil {
+		errors.ReturnWithError(c, carrier.ErrCarrierNotFound)
+		return
+	}
+
+	feed, err := calendar.BuildCarriersFeed(cr.Name+" ("+cr.Callsign+") Jumps", []entities.Carrier{cr})
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.Data(200, "text/calendar; charset=utf-8", []byte(feed))
+}
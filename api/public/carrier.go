@@ -6,8 +6,10 @@ import (
 	"ruehrstaat-backend/errors"
 	"ruehrstaat-backend/serialize"
 	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/presence"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 func publicGetCarrier(c *gin.Context) {
@@ -23,7 +25,8 @@ func publicGetCarrier(c *gin.Context) {
 		return
 	}
 
-	serialize.JSON[entities.Carrier](c, &serialize.CarrierSerializer{Limited: true, Full: false}, cr)
+	aboard := presence.Counts([]uuid.UUID{cr.ID})
+	serialize.JSON[entities.Carrier](c, &serialize.CarrierSerializer{Limited: true, Full: false, Aboard: aboard}, cr)
 }
 
 func publicGetAllCarriers(c *gin.Context) {
@@ -33,5 +36,11 @@ func publicGetAllCarriers(c *gin.Context) {
 		return
 	}
 
-	serialize.JSONArray(c, &serialize.CarrierSerializer{Limited: true, Full: false}, carriers)
+	carrierIds := []uuid.UUID{}
+	for _, cr := range carriers {
+		carrierIds = append(carrierIds, cr.ID)
+	}
+	aboard := presence.Counts(carrierIds)
+
+	serialize.JSONArray(c, &serialize.CarrierSerializer{Limited: true, Full: false, Aboard: aboard}, carriers)
 }
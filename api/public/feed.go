@@ -0,0 +1,124 @@
+package public
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/services/feed"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Maximum number of entries in a feed
+const feedEntryLimit = 50
+
+// GET /public/carrier/:id/feed.atom
+func publicGetCarrierFeed(c *gin.Context) {
+	carrierId := c.Param("id")
+	if carrierId == "" {
+		errors.ReturnWithError(c, carrier.ErrBadRequest)
+		return
+	}
+
+	cr := entities.Carrier{}
+	if res := db.DB.Where("id = ? AND is_public = ?", carrierId, true).First(&cr); res.Error != nil {
+		errors.ReturnWithError(c, carrier.ErrCarrierNotFound)
+		return
+	}
+
+	activities := []entities.CarrierActivity{}
+	if res := db.DB.Where("carrier_id = ?", cr.ID).Preload("Carrier").Order("created_at DESC").Limit(feedEntryLimit).Find(&activities); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	selfUrl := os.Getenv("BACKEND_URL") + "/v1/public/carrier/" + cr.ID.String() + "/feed.atom"
+	writeActivityFeed(c, "urn:uuid:"+cr.ID.String(), fmt.Sprintf("%s (%s)", cr.Name, cr.Callsign), selfUrl, cr.CreatedAt, activities)
+}
+
+// GET /public/feed.atom -> activity of all public carriers
+func publicGetSquadronFeed(c *gin.Context) {
+	activities := []entities.CarrierActivity{}
+	if res := db.DB.Joins("Carrier").Where("\"Carrier\".is_public = ?", true).Order("carrier_activities.created_at DESC").Limit(feedEntryLimit).Find(&activities); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	selfUrl := os.Getenv("BACKEND_URL") + "/v1/public/feed.atom"
+	writeActivityFeed(c, "tag:ruehrstaat.de,2024:carriers", "Ruehrstaat Squadron Carriers", selfUrl, time.Unix(0, 0), activities)
+}
+
+// Writes the activities as atom feed. Supports conditional requests via ETag and Last-Modified,
+// activities are ordered newest first.
+func writeActivityFeed(c *gin.Context, id string, title string, selfUrl string, fallbackUpdated time.Time, activities []entities.CarrierActivity) {
+	updated := fallbackUpdated
+	etag := `W/"empty"`
+	if len(activities) > 0 {
+		updated = activities[0].CreatedAt
+		etag = fmt.Sprintf(`W/"%s-%d"`, activities[0].ID, len(activities))
+	}
+
+	lastModified := updated.UTC().Truncate(time.Second)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	c.Header("Cache-Control", "public, max-age=300")
+
+	if notModified(c, etag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	atom := feed.NewAtomFeed(id, title, selfUrl, updated)
+	for _, activity := range activities {
+		entry := feed.AtomEntry{
+			ID:       "urn:uuid:" + activity.ID.String(),
+			Title:    activity.Summary,
+			Updated:  feed.FormatTime(activity.CreatedAt),
+			Category: &feed.AtomCategory{Term: string(activity.Type)},
+			Summary:  activity.Details,
+		}
+
+		if activity.Carrier != nil {
+			entry.Links = []feed.AtomLink{{Href: os.Getenv("FRONTEND_URL") + "/carrier/" + activity.CarrierID.String(), Rel: "alternate"}}
+		}
+
+		atom.AddEntry(entry)
+	}
+
+	data, err := atom.Render()
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, carrier.ErrInternalServerError)
+		return
+	}
+
+	c.Data(200, "application/atom+xml; charset=utf-8", data)
+}
+
+// Evaluates If-None-Match and If-Modified-Since, If-None-Match takes precedence (RFC 7232 section 6).
+func notModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := c.GetHeader("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ifModifiedSince := c.GetHeader("If-Modified-Since"); ifModifiedSince != "" {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.After(since)
+	}
+
+	return false
+}
@@ -13,5 +13,14 @@ func RegisterRoutes(api *gin.RouterGroup) {
 
 	publicCarrierApi := publicApi.Group("/carrier")
 	publicCarrierApi.GET("/:id", publicGetCarrier)
+	publicCarrierApi.GET("/:id/jumps.ics", publicGetCarrierCalendar)
+	publicCarrierApi.GET("/:id/feed.atom", publicGetCarrierFeed)
+	publicCarrierApi.GET("/:id/badge.svg", publicGetCarrierBadgeSVG)
+	publicCarrierApi.GET("/:id/badge.png", publicGetCarrierBadgePNG)
+
+	publicApi.GET("/feed.atom", publicGetSquadronFeed)
 	publicCarrierApi.GET("/", publicGetAllCarriers)
+
+	publicApi.GET("/operations", publicGetOperations)
+	publicApi.GET("/operations/:id", publicGetOperation)
 }
@@ -0,0 +1,70 @@
+package public
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/operations"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /public/operations -> upcoming public operations
+func publicGetOperations(c *gin.Context) {
+	list, err := operations.List(false, true)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	for i := range list {
+		onlyPublicCarriers(&list[i])
+	}
+
+	serialize.JSONArray[entities.Operation](c, &serialize.OperationSerializer{}, list)
+}
+
+// GET /public/operations/:id -> read-only view of a public operation including its timeline
+func publicGetOperation(c *gin.Context) {
+	operationId, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		errors.ReturnWithError(c, operations.ErrInvalidOperationId)
+		return
+	}
+
+	operation, err := operations.Find(operationId)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if !operation.IsPublic {
+		errors.ReturnWithError(c, operations.ErrOperationNotFound)
+		return
+	}
+
+	onlyPublicCarriers(operation)
+
+	timeline, err := operations.Timeline(operation)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"operation": serialize.Do[entities.Operation](&serialize.OperationSerializer{}, *operation),
+		"timeline":  serialize.DoArray[operations.TimelineEntry](&serialize.OperationTimelineSerializer{}, timeline),
+	})
+}
+
+// Private carriers assigned to a public operation are left out of the public view.
+func onlyPublicCarriers(operation *entities.Operation) {
+	carriers := []entities.Carrier{}
+	for _, cr := range operation.Carriers {
+		if cr.IsPublic {
+			carriers = append(carriers, cr)
+		}
+	}
+	operation.Carriers = carriers
+}
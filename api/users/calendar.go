@@ -0,0 +1,64 @@
+package users
+
+import (
+	"os"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Creates a new secret calendar feed url, previous urls stop working.
+func createCalendarToken(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	user, err := findUser(current, c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+	if user.ID != current.ID && !current.IsAdmin {
+		errors.ReturnWithError(c, auth.ErrForbidden)
+		return
+	}
+
+	token, err := auth.RegisterCalendarToken(user)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrServer)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"token": token,
+		"url":   os.Getenv("BACKEND_URL") + "/v1/calendar/" + token + "/jumps.ics",
+	})
+}
+
+func revokeCalendarToken(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	user, err := findUser(current, c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+	if user.ID != current.ID && !current.IsAdmin {
+		errors.ReturnWithError(c, auth.ErrForbidden)
+		return
+	}
+
+	if err := auth.RevokeCalendarTokens(user); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrServer)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
@@ -1,14 +1,38 @@
 package users
 
+import (
+	"time"
+)
+
 type editUserBody struct {
 	Email    string `json:"email"`
 	Nickname string `json:"nickname"`
 	CmdrName string `json:"cmdrName"`
 
+	HidePresence *bool `json:"hidePresence"`
+
 	IsBanned *bool `json:"isBanned"`
 	IsAdmin  *bool `json:"isAdmin"`
 }
 
+type createApiTokenBody struct {
+	Name      string     `json:"name" binding:"required,max=255"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+	// e.g. "carrier:connector" or "carrier:read:<carrier id>"
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+type updateApiTokenBody struct {
+	Name   *string   `json:"name" binding:"omitempty,min=1,max=255"`
+	Scopes *[]string `json:"scopes" binding:"omitempty,min=1"`
+}
+
+// Empty strings remove the key
+type integrationKeysBody struct {
+	InaraApiKey *string `json:"inaraApiKey"`
+	EdsmApiKey  *string `json:"edsmApiKey"`
+}
+
 type changeEmailBody struct {
 	NewEmail string  `json:"newEmail" binding:"required"`
 	Password string  `json:"password" binding:"required"`
@@ -5,6 +5,7 @@ import (
 	"ruehrstaat-backend/auth"
 	"ruehrstaat-backend/db"
 	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/services/presence"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -76,6 +77,22 @@ func editUser(c *gin.Context) {
 		return
 	}
 
+	// banned users must not keep using the tokens they already have
+	if userDTO.IsBanned != nil && *userDTO.IsBanned {
+		if err := auth.RevokeUserTokens(user.ID); err != nil {
+			c.Error(err)
+			errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+			return
+		}
+	}
+
+	// forget where the user currently is right away when opting out
+	if userDTO.HidePresence != nil && *userDTO.HidePresence {
+		if err := presence.Leave(user.ID); err != nil {
+			c.Error(err)
+		}
+	}
+
 	c.JSON(200, gin.H{"message": "User updated successfully"})
 }
 
@@ -31,6 +31,9 @@ func findUser(current *entities.User, userIdStr string) (*entities.User, *errors
 
 func getUser(c *gin.Context) {
 	current := auth.Extract(c)
+	if tokenUser, exists := c.Get("user"); exists {
+		current = tokenUser.(*entities.User)
+	}
 	if current == nil {
 		c.Error(auth.ErrInvalidToken)
 		errors.ReturnWithError(c, auth.ErrUnauthorized)
@@ -1,6 +1,9 @@
 package users
 
 import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
 	"ruehrstaat-backend/logging"
 
 	"github.com/gin-gonic/gin"
@@ -11,7 +14,7 @@ var log = logging.Logger{Package: "api-users"}
 func RegisterRoutes(api *gin.RouterGroup) {
 	usersApi := api.Group("/users")
 
-	usersApi.GET("/:id", getUser)
+	usersApi.GET("/:id", apiTokenMiddleware(), auth.RequireScope(entities.ScopeUserRead), getUser)
 	usersApi.PATCH("/:id", editUser)
 	usersApi.POST("/:id/activate", activateUser)
 	usersApi.POST("/activation/resend", resendUserActivation)
@@ -21,6 +24,11 @@ func RegisterRoutes(api *gin.RouterGroup) {
 	usersApi.POST("/link/discord", beginDiscordLink)
 	usersApi.GET("/link/discord/callback", discordLinkCallback)
 	usersApi.DELETE("/link/discord", unlinkDiscord)
+	usersApi.GET("/link/frontier", getFrontierLink)
+	usersApi.POST("/link/frontier", beginFrontierLink)
+	usersApi.GET("/link/frontier/callback", frontierLinkCallback)
+	usersApi.POST("/link/frontier/sync", syncFrontierLink)
+	usersApi.DELETE("/link/frontier", unlinkFrontier)
 	usersApi.POST("/link/fido2/begin", beginFido2Link)
 	usersApi.POST("/link/fido2/end", endFido2Link)
 	usersApi.GET("/link/fido2/all", getFido2Links)
@@ -33,8 +41,45 @@ func RegisterRoutes(api *gin.RouterGroup) {
 	usersApi.POST("/change-email/request", requestEmailChange)
 	usersApi.POST("/:id/change-email", changeEmail)
 	usersApi.PATCH("/locale", setLocale)
+	usersApi.POST("/:id/calendar", createCalendarToken)
+	usersApi.DELETE("/:id/calendar", revokeCalendarToken)
+	usersApi.GET("/:id/logistics", getUserLogisticsRequests)
+	usersApi.PUT("/:id/integrations", setIntegrationKeys)
+	usersApi.GET("/:id/tokens", getApiTokens)
+	usersApi.POST("/:id/tokens", createApiToken)
+	usersApi.PATCH("/:id/tokens/:tokenId", updateApiToken)
+	usersApi.DELETE("/:id/tokens/:tokenId", revokeApiToken)
+	usersApi.GET("/:id/oauth", getOAuthGrants)
+	usersApi.DELETE("/:id/oauth/:grantId", revokeOAuthGrant)
+	usersApi.GET("/:id/sessions", getSessions)
+	usersApi.DELETE("/:id/sessions", revokeSessions)
+	usersApi.DELETE("/:id/sessions/:sessionId", revokeSession)
+	usersApi.GET("/:id/security-events", getSecurityEvents)
 
 	adminGroup := usersApi.Group("/admin")
 	adminGroup.GET("/", adminGetUsers)
 	adminGroup.POST("/", adminCreateUser)
+	adminGroup.GET("/tokens", adminGetApiTokens)
+}
+
+// Lets routes that support it be used with api tokens, requests without token headers use the session as usual.
+func apiTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.HasApiTokenCredentials(c) {
+			return
+		}
+
+		user, token := auth.AuthenticateApiTokenUser(c)
+		if token == nil {
+			errors.MiddlewareAbortWithError(c, auth.ErrUnauthorized)
+			return
+		}
+		if user.IsBanned {
+			errors.MiddlewareAbortWithError(c, auth.ErrUserBanned)
+			return
+		}
+
+		c.Set("token", token)
+		c.Set("user", user)
+	}
 }
@@ -0,0 +1,76 @@
+package users
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sets the Inara and EDSM API keys used to sync the user's carriers. Keys are stored encrypted and never returned.
+func setIntegrationKeys(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	user, err := findUser(current, c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+	if user.ID != current.ID && !current.IsAdmin {
+		errors.ReturnWithError(c, auth.ErrForbidden)
+		return
+	}
+
+	dto := integrationKeysBody{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	inaraApiKey, ok := encryptIntegrationKey(c, dto.InaraApiKey, user.InaraApiKey)
+	if !ok {
+		return
+	}
+	edsmApiKey, ok := encryptIntegrationKey(c, dto.EdsmApiKey, user.EdsmApiKey)
+	if !ok {
+		return
+	}
+
+	user.InaraApiKey = inaraApiKey
+	user.EdsmApiKey = edsmApiKey
+	if res := db.DB.Model(user).Select("inara_api_key", "edsm_api_key").Updates(user); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToSaveToDB)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"hasInaraApiKey": user.InaraApiKey != nil,
+		"hasEdsmApiKey":  user.EdsmApiKey != nil,
+	})
+}
+
+// Returns the value to store for a submitted key: unchanged if not submitted, removed if empty, encrypted otherwise.
+func encryptIntegrationKey(c *gin.Context, submitted *string, stored *string) (*string, bool) {
+	if submitted == nil {
+		return stored, true
+	}
+	if *submitted == "" {
+		return nil, true
+	}
+
+	encrypted, err := util.Encrypt(*submitted)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrServer)
+		return nil, false
+	}
+	return &encrypted, true
+}
@@ -0,0 +1,191 @@
+package users
+
+import (
+	"net/http"
+	"os"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/auth/frontier"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/capi"
+	"ruehrstaat-backend/util"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func findFrontierLink(user *entities.User) (*entities.FrontierLink, *errors.RstError) {
+	link := &entities.FrontierLink{}
+	res := db.DB.Where("user_id = ?", user.ID).Limit(1).Find(link)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, auth.ErrFrontierNotLinked
+	}
+	return link, nil
+}
+
+func getFrontierLink(c *gin.Context) {
+	user := auth.Extract(c)
+	if user == nil {
+		c.Error(auth.ErrInvalidToken)
+		errors.ReturnWithError(c, auth.ErrUnauthorized)
+		return
+	}
+
+	link, err := findFrontierLink(user)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSON[entities.FrontierLink](c, &serialize.FrontierLinkSerializer{}, *link)
+}
+
+func beginFrontierLink(c *gin.Context) {
+	user := auth.Extract(c)
+	if user == nil {
+		c.Error(auth.ErrInvalidToken)
+		errors.ReturnWithError(c, auth.ErrUnauthorized)
+		return
+	}
+
+	if _, err := findFrontierLink(user); err == nil {
+		errors.ReturnWithError(c, auth.ErrFrontierAlreadyLinked)
+		return
+	} else if err != auth.ErrFrontierNotLinked {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	redirectTo := c.Query("redirect_to")
+	if redirectTo == "" {
+		redirectTo = os.Getenv("FRONTEND_URL")
+	}
+
+	codeVerifier, err := frontier.GenerateCodeVerifier()
+	if err != nil {
+		panic(err)
+	}
+
+	payload := map[string]interface{}{
+		"redirect_to":   redirectTo,
+		"user_id":       user.ID,
+		"code_verifier": codeVerifier,
+	}
+
+	state := cache.BeginState("user_frontier_link", payload, time.Minute*5)
+	url := frontier.GetOAuthUrl(frontier.LinkingConf, state, codeVerifier)
+
+	c.JSON(200, gin.H{"url": url})
+}
+
+func frontierLinkCallback(c *gin.Context) {
+	state := c.Query("state")
+	if state == "" {
+		errors.ReturnWithError(c, auth.ErrStateIsMissing)
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		errors.ReturnWithError(c, auth.ErrCodeIsMissing)
+		return
+	}
+
+	payload := struct {
+		RedirectTo   string `json:"redirect_to"`
+		UserId       string `json:"user_id"`
+		CodeVerifier string `json:"code_verifier"`
+	}{}
+	if !cache.EndState("user_frontier_link", state, &payload) {
+		errors.ReturnWithError(c, auth.ErrInvalidState)
+		return
+	}
+
+	redirectTo := payload.RedirectTo
+
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", payload.UserId).First(user); res.Error != nil {
+		c.Redirect(http.StatusTemporaryRedirect, redirectTo+"?success=false&rs=1")
+		return
+	}
+
+	ok, token, account := frontier.RetrieveOAuthAccount(frontier.LinkingConf, code, payload.CodeVerifier)
+	if !ok {
+		c.Redirect(http.StatusTemporaryRedirect, redirectTo+"?success=false&rs=1")
+		return
+	}
+
+	refreshToken, err := util.Encrypt(token.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		c.Redirect(http.StatusTemporaryRedirect, redirectTo+"?success=false&rs=1")
+		return
+	}
+
+	link := &entities.FrontierLink{
+		UserID:       user.ID,
+		CustomerID:   account.CustomerID.String(),
+		RefreshToken: refreshToken,
+	}
+	if res := db.DB.Create(link); res.Error != nil {
+		c.Error(res.Error)
+		c.Redirect(http.StatusTemporaryRedirect, redirectTo+"?success=false&rs=1")
+		return
+	}
+
+	// pull the carrier right away instead of waiting for the next scheduled sync
+	go capi.DefaultSyncer().SyncLink(link)
+
+	c.Redirect(http.StatusTemporaryRedirect, redirectTo+"?success=true&rs=1")
+}
+
+func syncFrontierLink(c *gin.Context) {
+	user := auth.Extract(c)
+	if user == nil {
+		c.Error(auth.ErrInvalidToken)
+		errors.ReturnWithError(c, auth.ErrUnauthorized)
+		return
+	}
+
+	link, err := findFrontierLink(user)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	// the outcome is stored on the link and returned below
+	if err := capi.DefaultSyncer().SyncLink(link); err != nil {
+		c.Error(err)
+	}
+
+	serialize.JSON[entities.FrontierLink](c, &serialize.FrontierLinkSerializer{}, *link)
+}
+
+func unlinkFrontier(c *gin.Context) {
+	user := auth.Extract(c)
+	if user == nil {
+		c.Error(auth.ErrInvalidToken)
+		errors.ReturnWithError(c, auth.ErrUnauthorized)
+		return
+	}
+
+	link, err := findFrontierLink(user)
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	// hard delete, the account may be linked again later
+	if res := db.DB.Unscoped().Delete(link); res.Error != nil {
+		c.Error(res.Error)
+		panic(res.Error)
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
@@ -0,0 +1,37 @@
+package users
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"ruehrstaat-backend/services/logistics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GET /users/:id/logistics -> logistics requests filed by the user
+func getUserLogisticsRequests(c *gin.Context) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return
+	}
+
+	user, err := findUser(current, c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+	if user.ID != current.ID && !current.IsAdmin {
+		errors.ReturnWithError(c, auth.ErrForbidden)
+		return
+	}
+
+	requests, err := logistics.ListForUser(user, c.Query("status"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	serialize.JSONArray[entities.LogisticsRequest](c, &serialize.LogisticsRequestSerializer{}, requests)
+}
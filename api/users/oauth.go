@@ -0,0 +1,64 @@
+package users
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /users/:id/oauth -> apps the user authorized
+func getOAuthGrants(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	grants := []entities.OAuthGrant{}
+	if res := db.DB.Where("user_id = ? AND is_revoked = ? AND refresh_expires_at > ?", user.ID, false, time.Now()).Preload("Client").Order("created_at DESC").Find(&grants); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+
+	serialize.JSONArray[entities.OAuthGrant](c, &serialize.OAuthGrantSerializer{}, grants)
+}
+
+// DELETE /users/:id/oauth/:grantId -> revokes the authorization, the app loses access immediately
+func revokeOAuthGrant(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	grantId, err := uuid.Parse(c.Param("grantId"))
+	if err != nil {
+		errors.ReturnWithError(c, auth.ErrInvalidUUID)
+		return
+	}
+
+	grant := &entities.OAuthGrant{}
+	res := db.DB.Where("id = ? AND user_id = ?", grantId, user.ID).Limit(1).Find(grant)
+	if res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+	if res.RowsAffected == 0 {
+		errors.ReturnWithError(c, auth.ErrOAuthGrantNotFound)
+		return
+	}
+
+	if err := auth.RevokeOAuthGrant(grant); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
@@ -94,6 +94,13 @@ func requestPasswordReset(c *gin.Context) {
 		return
 	}
 
+	// every request sends an email, so all of them count
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP(), Email: email}
+	if !auth.CheckAttempts(c, auth.AttemptPasswordResetRequest, attemptKeys) {
+		return
+	}
+	auth.RecordFailedAttempt(auth.AttemptPasswordResetRequest, attemptKeys)
+
 	user := &entities.User{}
 	if res := db.DB.Where("email = ?", email).First(user); res.Error != nil {
 		if res.Error == gorm.ErrRecordNotFound {
@@ -144,8 +151,14 @@ func resetPassword(c *gin.Context) {
 		return
 	}
 
+	attemptKeys := auth.AttemptKeys{IP: c.ClientIP(), UserID: userID}
+	if !auth.CheckAttempts(c, auth.AttemptPasswordReset, attemptKeys) {
+		return
+	}
+
 	if err := auth.ResetPassword(userID, token, dto.Password, dto.Otp); err != nil {
 		if err == auth.ErrInvalidResetToken {
+			auth.RecordFailedAttempt(auth.AttemptPasswordReset, attemptKeys)
 			errors.ReturnWithError(c, err)
 			return
 		}
@@ -158,6 +171,7 @@ func resetPassword(c *gin.Context) {
 
 		if err == auth.ErrUserOtpWrong {
 			c.Error(err)
+			auth.RecordFailedAttempt(auth.AttemptPasswordReset, attemptKeys)
 			errors.ReturnWithError(c, auth.ErrUserOtpWrong)
 			return
 		}
@@ -165,6 +179,8 @@ func resetPassword(c *gin.Context) {
 		c.Error(err)
 		panic(err)
 	}
+
+	auth.ClearFailedAttempts(auth.AttemptPasswordReset, attemptKeys)
 }
 
 func changePassword(c *gin.Context) {
@@ -186,7 +202,8 @@ func changePassword(c *gin.Context) {
 		return
 	}
 
-	if err := auth.ChangePassword(user, dto.OldPassword, dto.NewPassword, dto.Otp); err != nil {
+	token, err := auth.ChangePassword(user, dto.OldPassword, dto.NewPassword, dto.Otp, auth.NewSessionMetadata(c, auth.LoginMethodPassword))
+	if err != nil {
 		if err == auth.ErrInvalidCredentials {
 			errors.ReturnWithError(c, err)
 			return
@@ -201,7 +218,9 @@ func changePassword(c *gin.Context) {
 		panic(err)
 	}
 
-	c.JSON(200, gin.H{"message": "Password changed successfully"})
+	// all sessions ended with the change, the device changing the password gets a new one
+	c.SetCookie("refresh_token", token.RefreshToken, 60*60*24*30, "/", "", false, true)
+	c.JSON(200, gin.H{"message": "Password changed successfully", "token": token.IdenityToken, "expiresAt": token.ExpiresAt})
 }
 
 func requestEmailChange(c *gin.Context) {
@@ -0,0 +1,89 @@
+package users
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GET /users/:id/sessions -> devices the user is logged in on, newest use first
+func getSessions(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := auth.GetSessions(user.ID)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+
+	serialize.JSONArray[entities.RefreshToken](c, &serialize.SessionSerializer{CurrentID: auth.SessionIDFromContext(c)}, sessions)
+}
+
+// DELETE /users/:id/sessions/:sessionId -> logs the user out on that device
+func revokeSession(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	sessionId, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		errors.ReturnWithError(c, auth.ErrInvalidUUID)
+		return
+	}
+
+	if err := auth.RevokeSession(user.ID, sessionId); err != nil {
+		if err == auth.ErrSessionNotFound {
+			errors.ReturnWithError(c, err)
+			return
+		}
+
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// DELETE /users/:id/sessions -> ends all sessions of the user, e.g. by an admin after an account was compromised
+func revokeSessions(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	if err := auth.RevokeUserTokens(user.ID); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// GET /users/:id/security-events -> latest security events of the account, e.g. reused refresh tokens
+func getSecurityEvents(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	events := []entities.SecurityEvent{}
+	if res := db.DB.Where("user_id = ?", user.ID).Order("created_at DESC").Limit(100).Find(&events); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+
+	serialize.JSONArray[entities.SecurityEvent](c, &serialize.SecurityEventSerializer{}, events)
+}
@@ -0,0 +1,217 @@
+package users
+
+import (
+	"ruehrstaat-backend/api/dtoerr"
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/serialize"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const maxApiTokenLifetime = time.Hour * 24 * 365
+
+// Resolves the user in the path, only the user themselves and admins may manage their tokens.
+func authorizeTokenOwner(c *gin.Context) (*entities.User, *entities.User, bool) {
+	current, authorized := auth.AutoAuthorize(c)
+	if !authorized {
+		return nil, nil, false
+	}
+
+	user, err := findUser(current, c.Param("id"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return nil, nil, false
+	}
+	if user.ID != current.ID && !current.IsAdmin {
+		errors.ReturnWithError(c, auth.ErrForbidden)
+		return nil, nil, false
+	}
+
+	return current, user, true
+}
+
+// Removes duplicate scope entries while keeping their order.
+func uniqueScopes(scopes []string) []string {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
+
+func findApiToken(user *entities.User, tokenIdStr string) (*entities.ApiToken, *errors.RstError) {
+	tokenId, err := uuid.Parse(tokenIdStr)
+	if err != nil {
+		return nil, auth.ErrInvalidUUID
+	}
+
+	token := &entities.ApiToken{}
+	res := db.DB.Where("id = ? AND user_id = ? AND grant_id IS NULL", tokenId, user.ID).Limit(1).Find(token)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, auth.ErrApiTokenNotFound
+	}
+	return token, nil
+}
+
+// GET /users/:id/tokens -> active tokens of the user, ?all=true includes revoked and expired ones
+func getApiTokens(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	// access tokens of oauth apps are managed through their grants
+	query := db.DB.Where("user_id = ? AND grant_id IS NULL", user.ID)
+	if c.Query("all") != "true" {
+		query = query.Where("is_revoked = ? AND expires_at > ?", false, time.Now())
+	}
+
+	tokens := []entities.ApiToken{}
+	if res := query.Order("created_at DESC").Find(&tokens); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+
+	serialize.JSONArray[entities.ApiToken](c, &serialize.ApiTokenSerializer{}, tokens)
+}
+
+// POST /users/:id/tokens -> creates a token, the cleartext token is only part of this response
+func createApiToken(c *gin.Context) {
+	current, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	dto := createApiTokenBody{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	expiresAt := time.Now().Add(maxApiTokenLifetime)
+	if dto.ExpiresAt != nil {
+		if dto.ExpiresAt.Before(time.Now()) || dto.ExpiresAt.After(expiresAt) {
+			errors.ReturnWithError(c, auth.ErrInvalidTokenExpiry)
+			return
+		}
+		expiresAt = *dto.ExpiresAt
+	}
+
+	scopes := uniqueScopes(dto.Scopes)
+	if err := auth.ValidateScopes(current, user, scopes); err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	token, tokenClear, err := auth.RegisterAPIToken(user, dto.Name, expiresAt, scopes)
+	if err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrServer)
+		return
+	}
+
+	c.JSON(200, gin.H{
+		"token":    tokenClear,
+		"userId":   user.ID,
+		"apiToken": serialize.Do[entities.ApiToken](&serialize.ApiTokenSerializer{}, *token),
+	})
+}
+
+// PATCH /users/:id/tokens/:tokenId -> renames the token or changes its scopes
+func updateApiToken(c *gin.Context) {
+	current, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	token, err := findApiToken(user, c.Param("tokenId"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	dto := updateApiTokenBody{}
+	if err := c.ShouldBindJSON(&dto); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, dtoerr.InvalidDTO)
+		return
+	}
+
+	if dto.Name != nil {
+		token.Name = *dto.Name
+	}
+	if dto.Scopes != nil {
+		scopes := uniqueScopes(*dto.Scopes)
+		if err := auth.ValidateScopes(current, user, scopes); err != nil {
+			errors.ReturnWithError(c, err)
+			return
+		}
+		token.Scopes = scopes
+	}
+
+	if res := db.DB.Model(token).Select("name", "scopes").Updates(token); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+		return
+	}
+
+	serialize.JSON[entities.ApiToken](c, &serialize.ApiTokenSerializer{}, *token)
+}
+
+// DELETE /users/:id/tokens/:tokenId -> revokes the token
+func revokeApiToken(c *gin.Context) {
+	_, user, ok := authorizeTokenOwner(c)
+	if !ok {
+		return
+	}
+
+	token, err := findApiToken(user, c.Param("tokenId"))
+	if err != nil {
+		errors.ReturnWithError(c, err)
+		return
+	}
+
+	if err := auth.RevokeAPIToken(token); err != nil {
+		c.Error(err)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToUpdateDB)
+		return
+	}
+
+	c.JSON(200, gin.H{"success": true})
+}
+
+// GET /users/admin/tokens -> active tokens of all users, ?all=true includes revoked and expired ones
+func adminGetApiTokens(c *gin.Context) {
+	_, authorized := auth.AutoAuthorizeAdmin(c)
+	if !authorized {
+		return
+	}
+
+	query := db.DB.Where("grant_id IS NULL")
+	if c.Query("all") != "true" {
+		query = query.Where("is_revoked = ? AND expires_at > ?", false, time.Now())
+	}
+
+	tokens := []entities.ApiToken{}
+	if res := query.Order("created_at DESC").Limit(100).Find(&tokens); res.Error != nil {
+		c.Error(res.Error)
+		errors.ReturnWithError(c, auth.ErrAdminFailedToGetFromDB)
+		return
+	}
+
+	serialize.JSONArray[entities.ApiToken](c, &serialize.ApiTokenSerializer{WithUser: true}, tokens)
+}
@@ -4,11 +4,12 @@ import (
 	"ruehrstaat-backend/db"
 	"ruehrstaat-backend/db/entities"
 	"ruehrstaat-backend/errors"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pquerna/otp/totp"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
 func CheckUserLoginAllowance(user *entities.User) *errors.RstError {
@@ -24,16 +25,18 @@ func CheckUserLoginAllowance(user *entities.User) *errors.RstError {
 }
 
 // Tries to login a user with the given email and password.
-// Returns a token pair if successful, otherwise an error.
-func Login(email string, password string, otp *string) (*TokenPair, *entities.User, *errors.RstError) {
+// Returns the token pair of a new session if successful, otherwise an error.
+func Login(email string, password string, otp *string, metadata SessionMetadata) (*TokenPair, *entities.User, *errors.RstError) {
 	user := &entities.User{}
 
 	if res := db.DB.Where("email = ?", email).First(user); res.Error != nil {
 		return nil, nil, ErrUserNotFound
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, nil, ErrInvalidCredentials
+	// locked accounts are answered like a wrong password, even the right one. Telling them apart would confirm
+	// guesses during the lockout, and a distinct answer would reveal that an account exists for the email.
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil || IsAccountLocked(user.ID) {
+		return nil, user, ErrInvalidCredentials
 	}
 
 	if err := CheckUserLoginAllowance(user); err != nil {
@@ -53,34 +56,17 @@ func Login(email string, password string, otp *string) (*TokenPair, *entities.Us
 		}
 	}
 
-	if res := db.DB.Model(&entities.RefreshToken{}).Where("user_id = ?", user.ID).Update("is_revoked", true); res.Error != nil {
-		return nil, user, errors.NewDBErrorFromError(res.Error)
-	}
-
-	tokenPair, err := generatePair(user.ID, nil)
+	tokenPair, err := createSession(user.ID, metadata)
 	if err != nil {
 		return nil, user, err
 	}
 
-	return &tokenPair, user, nil
+	return tokenPair, user, nil
 }
 
-func CreateTokenPairForUser(user *entities.User) (*TokenPair, *errors.RstError) {
-	tokenPair, err := generatePair(user.ID, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	refreshToken := &entities.RefreshToken{
-		UserID: user.ID,
-		Token:  tokenPair.RefreshToken,
-	}
-
-	if res := db.DB.Create(refreshToken); res.Error != nil {
-		return nil, errors.NewDBErrorFromError(res.Error)
-	}
-
-	return &tokenPair, nil
+// Creates a new session for a user who already authenticated, e.g. with discord or a security key.
+func CreateTokenPairForUser(user *entities.User, metadata SessionMetadata) (*TokenPair, *errors.RstError) {
+	return createSession(user.ID, metadata)
 }
 
 // Tries to use a backup code to do a two factor authentication.
@@ -109,21 +95,28 @@ func TryBackupCodes(user *entities.User, otp *string) *errors.RstError {
 	return nil
 }
 
-// Logs out the user with the given refresh token.
-// If all is true, all refresh tokens for the user will be deleted.
-func Logout(refreshToken string, all bool) *errors.RstError {
-	decoded, err := decodeToken(getRefreshTokenSecret(), refreshToken)
+// Logs out the user with the given refresh token, the identity token of the session is denylisted if given.
+// If all is true, all sessions of the user will be ended.
+func Logout(refreshToken string, identityToken string, all bool) *errors.RstError {
+	decoded, err := decodeToken(refreshTokenAudience, refreshToken)
 	if err != nil {
 		return err
 	}
 
 	if all {
-		if res := db.DB.Where("user_id = ?", decoded.Subject).Delete(&entities.RefreshToken{}); res.Error != nil {
-			return errors.NewDBErrorFromError(res.Error)
-		}
-	} else {
-		if res := db.DB.Where("token = ? OR (user_id = ? AND is_revoked = ?)", refreshToken, decoded.Subject, true).Delete(&entities.RefreshToken{}); res.Error != nil {
-			return errors.NewDBErrorFromError(res.Error)
+		return RevokeUserTokens(decoded.Subject)
+	}
+
+	if res := db.DB.Where("id = ? AND user_id = ? AND token = ?", decoded.SessionID, decoded.Subject, refreshToken).Delete(&entities.RefreshToken{}); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	denySession(decoded.SessionID)
+	denyToken(decoded)
+
+	if identityToken != "" {
+		// the identity token might already be expired, the session is over either way
+		if decodedIdentity, err := decodeToken(identityTokenAudience, identityToken); err == nil && decodedIdentity.Subject == decoded.Subject {
+			denyToken(decodedIdentity)
 		}
 	}
 
@@ -131,53 +124,86 @@ func Logout(refreshToken string, all bool) *errors.RstError {
 }
 
 // Refreshes the access token with the given refresh token. If the refresh token is invalid, an error is returned.
-// The refresh token will be rotated, so that the old one is no longer valid.
-func Refresh(refreshToken string) (*TokenPair, *errors.RstError) {
-	decoded, err := decodeToken(getRefreshTokenSecret(), refreshToken)
+// The refresh token of the session will be rotated, so that the old one is no longer valid.
+func Refresh(refreshToken string, metadata SessionMetadata) (*TokenPair, *errors.RstError) {
+	decoded, err := decodeToken(refreshTokenAudience, refreshToken)
 	if err != nil {
 		return nil, ErrUsedRefreshToken
 	}
 
-	existing := &entities.RefreshToken{}
-	if res := db.DB.Where("token = ? AND user_id = ?", refreshToken, decoded.Subject).First(existing); res.Error != nil {
-		if res.Error != gorm.ErrRecordNotFound {
-			return nil, errors.NewDBErrorFromError(res.Error)
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", decoded.Subject).First(user); res.Error != nil {
+		return nil, ErrUsedRefreshToken
+	}
+	if !isTokenActive(decoded, user) {
+		return nil, ErrUsedRefreshToken
+	}
+
+	session := &entities.RefreshToken{}
+	res := db.DB.Where("id = ? AND user_id = ?", decoded.SessionID, decoded.Subject).Limit(1).Find(session)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrUsedRefreshToken
+	}
+
+	if session.Token != refreshToken {
+		if err := handleRefreshTokenReuse(user, session, metadata); err != nil {
+			return nil, err
 		}
-	} else if existing.IsRevoked {
-		Logout(refreshToken, true)
 		return nil, ErrUsedRefreshToken
 	}
 
-	tokenPair, err := generatePair(decoded.Subject, nil)
+	// the session ends at its absolute expiration no matter how often it is refreshed
+	absoluteExpiration := session.ExpiresAt.Unix()
+	tokenPair, err := generatePair(decoded.Subject, session.ID, &absoluteExpiration)
+	if err == ErrAbsoluteExpReached {
+		if err := endSession(session); err != nil {
+			return nil, err
+		}
+		return nil, ErrAbsoluteExpReached
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if res := db.DB.Model(&entities.RefreshToken{}).Where("user_id = ? AND token = ?", decoded.Subject, refreshToken).Update("is_revoked", true); res.Error != nil {
+	// only rotate if no concurrent refresh rotated the token in the meantime
+	res = db.DB.Model(&entities.RefreshToken{}).Where("id = ? AND token = ?", session.ID, refreshToken).Updates(map[string]interface{}{
+		"token":        tokenPair.RefreshToken,
+		"ip":           metadata.IP,
+		"user_agent":   metadata.UserAgent,
+		"last_used_at": time.Now(),
+	})
+	if res.Error != nil {
 		return nil, errors.NewDBErrorFromError(res.Error)
 	}
-
-	refreshTokenEntity := &entities.RefreshToken{
-		UserID: decoded.Subject,
-		Token:  tokenPair.RefreshToken,
+	if res.RowsAffected == 0 {
+		return nil, ErrUsedRefreshToken
 	}
 
-	if res := db.DB.Create(refreshTokenEntity); res.Error != nil {
-		return nil, errors.NewDBErrorFromError(res.Error)
+	return &tokenPair, nil
+}
+
+// Identity token from the Authorization header, empty if there is none.
+func IdentityTokenFromHeader(ctx *gin.Context) string {
+	header := ctx.GetHeader("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
 	}
 
-	return &tokenPair, nil
+	return strings.TrimPrefix(header, "Bearer ")
 }
 
 // Extracts the user from the given context by extracting the token from the Authorization header.
+// Revoked tokens and tokens issued before the user's sessions were ended are rejected.
 func Extract(ctx *gin.Context) *entities.User {
-	idenityToken := ctx.GetHeader("Authorization")
+	idenityToken := IdentityTokenFromHeader(ctx)
 	if idenityToken == "" {
 		return nil
 	}
 
-	idenityToken = idenityToken[7:]
-	decoded, err := decodeToken(getIdentityTokenSecret(), idenityToken)
+	decoded, err := decodeToken(identityTokenAudience, idenityToken)
 	if err != nil {
 		return nil
 	}
@@ -187,5 +213,10 @@ func Extract(ctx *gin.Context) *entities.User {
 		return nil
 	}
 
+	if !isTokenActive(decoded, user) {
+		return nil
+	}
+
+	ctx.Set("sessionId", decoded.SessionID)
 	return user
 }
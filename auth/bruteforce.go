@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"log"
+	"math"
+	"net/url"
+	"os"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/mailer"
+	"ruehrstaat-backend/mailer/mails"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Brute-force protection for the login endpoints. Failed attempts are counted in sliding windows per IP,
+// per email and per account. After a few free failures every further attempt has to wait progressively
+// longer, accounts reaching their limit are locked until the lockout expires or the user follows the
+// unlock link sent by email.
+
+type AttemptEndpoint string
+
+const (
+	AttemptLogin                AttemptEndpoint = "login"
+	AttemptLoginTotp            AttemptEndpoint = "login_totp"
+	AttemptPasswordResetRequest AttemptEndpoint = "password_reset_request"
+	AttemptPasswordReset        AttemptEndpoint = "password_reset"
+	AttemptQuicklogin           AttemptEndpoint = "quicklogin"
+
+	maxAttemptDelay = time.Minute
+)
+
+type attemptLimits struct {
+	// failures within the window, 0 disables the respective counter
+	IP      int
+	Email   int
+	Account int
+
+	Window time.Duration
+	// accounts reaching the account limit are locked for this long, 0 only throttles
+	Lockout time.Duration
+	// failures before delays kick in, the delay doubles with every further failure
+	FreeAttempts int
+}
+
+var defaultAttemptLimits = map[AttemptEndpoint]attemptLimits{
+	AttemptLogin:                {IP: 30, Email: 10, Account: 10, Window: time.Minute * 15, Lockout: time.Minute * 30, FreeAttempts: 3},
+	AttemptLoginTotp:            {IP: 30, Account: 5, Window: time.Minute * 15, Lockout: time.Minute * 30, FreeAttempts: 2},
+	AttemptPasswordResetRequest: {IP: 10, Email: 3, Window: time.Hour, FreeAttempts: 3},
+	AttemptPasswordReset:        {IP: 20, Account: 5, Window: time.Hour, Lockout: time.Minute * 30, FreeAttempts: 2},
+	AttemptQuicklogin:           {IP: 10, Account: 10, Window: time.Minute * 15, FreeAttempts: 3},
+}
+
+// Whoever an attempt is counted for, empty values are not counted.
+type AttemptKeys struct {
+	IP     string
+	Email  string
+	UserID uuid.UUID
+}
+
+// Limits of the endpoint, configured via AUTH_LIMIT_<ENDPOINT> (e.g. AUTH_LIMIT_LOGIN="ip=30,email=10,account=10,window=15m,lockout=30m,free=3").
+// Settings that are not given keep their default.
+func limitsFor(endpoint AttemptEndpoint) attemptLimits {
+	limits := defaultAttemptLimits[endpoint]
+
+	config := os.Getenv("AUTH_LIMIT_" + strings.ToUpper(string(endpoint)))
+	for _, setting := range strings.Split(config, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(setting), "=")
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "window", "lockout":
+			duration, err := time.ParseDuration(value)
+			if err != nil || duration < 0 {
+				log.Println("Invalid " + name + " in AUTH_LIMIT_" + strings.ToUpper(string(endpoint)))
+				continue
+			}
+			if name == "window" && duration > 0 {
+				limits.Window = duration
+			} else if name == "lockout" {
+				limits.Lockout = duration
+			}
+		case "ip", "email", "account", "free":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				log.Println("Invalid " + name + " in AUTH_LIMIT_" + strings.ToUpper(string(endpoint)))
+				continue
+			}
+			switch name {
+			case "ip":
+				limits.IP = count
+			case "email":
+				limits.Email = count
+			case "account":
+				limits.Account = count
+			case "free":
+				limits.FreeAttempts = count
+			}
+		}
+	}
+
+	return limits
+}
+
+// Counters the attempt is checked against, paired with their limit.
+func attemptCounters(limits attemptLimits, keys AttemptKeys) map[string]int {
+	counters := map[string]int{}
+	if keys.IP != "" && limits.IP > 0 {
+		counters["ip:"+keys.IP] = limits.IP
+	}
+	if keys.Email != "" && limits.Email > 0 {
+		counters["email:"+strings.ToLower(strings.TrimSpace(keys.Email))] = limits.Email
+	}
+	if keys.UserID != uuid.Nil && limits.Account > 0 {
+		counters["account:"+keys.UserID.String()] = limits.Account
+	}
+	return counters
+}
+
+// Time to wait after the latest failure, doubling with every failure beyond the free attempts.
+func attemptDelay(failures int64, freeAttempts int) time.Duration {
+	excess := failures - int64(freeAttempts)
+	if excess <= 0 {
+		return 0
+	}
+	if excess > 6 {
+		return maxAttemptDelay
+	}
+	return time.Second << (excess - 1)
+}
+
+// Checks whether another attempt is allowed, otherwise writes the error response with a Retry-After header.
+func CheckAttempts(c *gin.Context, endpoint AttemptEndpoint, keys AttemptKeys) bool {
+	if keys.UserID != uuid.Nil && IsAccountLocked(keys.UserID) {
+		errors.ReturnWithError(c, ErrAccountLocked)
+		return false
+	}
+
+	limits := limitsFor(endpoint)
+
+	var wait time.Duration
+	for counter, limit := range attemptCounters(limits, keys) {
+		failures, latest := cache.CountWindow("attempts:"+string(endpoint), counter, limits.Window)
+		if failures == 0 {
+			continue
+		}
+
+		// the oldest failure leaving the window would allow the next attempt, waiting for the whole window is an upper bound
+		if failures >= int64(limit) {
+			wait = max(wait, time.Until(latest.Add(limits.Window)))
+			continue
+		}
+
+		wait = max(wait, time.Until(latest.Add(attemptDelay(failures, limits.FreeAttempts))))
+	}
+
+	if wait > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		errors.ReturnWithError(c, ErrTooManyAttempts)
+		return false
+	}
+
+	return true
+}
+
+// Counts a failed attempt, the account is locked once it reaches the account limit of an endpoint with lockouts.
+func RecordFailedAttempt(endpoint AttemptEndpoint, keys AttemptKeys) {
+	limits := limitsFor(endpoint)
+
+	for counter, limit := range attemptCounters(limits, keys) {
+		failures := cache.AddToWindow("attempts:"+string(endpoint), counter, limits.Window)
+
+		if strings.HasPrefix(counter, "account:") && failures >= int64(limit) && limits.Lockout > 0 {
+			if err := lockAccount(keys, endpoint, limits.Lockout); err != nil {
+				log.Println("Failed to lock account", keys.UserID, err.Error())
+			}
+		}
+	}
+}
+
+// Forgets the failures of the email and account after a successful attempt, failures of the IP stay.
+func ClearFailedAttempts(endpoint AttemptEndpoint, keys AttemptKeys) {
+	keys.IP = ""
+	for counter := range attemptCounters(limitsFor(endpoint), keys) {
+		cache.ClearWindow("attempts:"+string(endpoint), counter)
+	}
+}
+
+// Whether the email reached its limit of the endpoint, no matter if an account exists for it.
+func IsEmailExhausted(endpoint AttemptEndpoint, email string) bool {
+	limits := limitsFor(endpoint)
+	for counter, limit := range attemptCounters(limits, AttemptKeys{Email: email}) {
+		failures, _ := cache.CountWindow("attempts:"+string(endpoint), counter, limits.Window)
+		return failures >= int64(limit)
+	}
+	return false
+}
+
+func IsAccountLocked(userID uuid.UUID) bool {
+	return cache.HasState("account_lock", userID.String())
+}
+
+func lockAccount(keys AttemptKeys, endpoint AttemptEndpoint, lockout time.Duration) *errors.RstError {
+	if IsAccountLocked(keys.UserID) {
+		return nil
+	}
+
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", keys.UserID).First(user); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	// the unlock link is only valid as long as the lockout
+	token, err := generateToken(accountUnlockTokenAudience, user.ID.String(), time.Now().Add(lockout).Unix())
+	if err != nil {
+		return err
+	}
+	cache.BeginSpecificState("account_lock", user.ID.String(), token, lockout)
+
+	log.Println("Locked account", user.ID, "after too many failed", endpoint, "attempts")
+
+	event := &entities.SecurityEvent{
+		UserID:  user.ID,
+		Type:    entities.SecurityEventAccountLocked,
+		IP:      keys.IP,
+		Details: "Too many failed " + string(endpoint) + " attempts, locked for " + lockout.String(),
+	}
+	if res := db.DB.Create(event); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	if err := mailer.SendMailGraceful(user.Email, mails.AccountLockedMail{
+		UserID:   user.ID,
+		Nickname: user.Nickname,
+		Token:    url.QueryEscape(token),
+		Until:    time.Now().Add(lockout),
+	}, user.Locale); err != nil {
+		log.Println("Failed to send account locked mail to user", user.ID)
+	}
+
+	return nil
+}
+
+// Lifts the lockout with the token of the unlock email and forgets the failed attempts of the account.
+func UnlockAccount(userID uuid.UUID, token string) *errors.RstError {
+	unescaped, err := url.QueryUnescape(token)
+	if err != nil {
+		return ErrInvalidUnlockToken
+	}
+
+	decoded, rerr := decodeToken(accountUnlockTokenAudience, unescaped)
+	if rerr != nil || decoded.Subject != userID {
+		return ErrInvalidUnlockToken
+	}
+
+	var lockToken string
+	if !cache.GetState("account_lock", userID.String(), &lockToken) || lockToken != unescaped {
+		return ErrInvalidUnlockToken
+	}
+
+	var ended string
+	cache.EndState("account_lock", userID.String(), &ended)
+
+	for endpoint := range defaultAttemptLimits {
+		ClearFailedAttempts(endpoint, AttemptKeys{UserID: userID})
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+
+	"gorm.io/gorm"
+)
+
+// Creates a new calendar feed token for the user, revoking all previous ones.
+// Returns the cleartext token, only its hash is stored.
+func RegisterCalendarToken(user *entities.User) (string, *errors.RstError) {
+	tokenClear, err := util.GenerateRandomString(48)
+	if err != nil {
+		return "", errors.NewFromError(err)
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Model(&entities.CalendarToken{}).Where("user_id = ?", user.ID).Update("is_revoked", true); res.Error != nil {
+			return res.Error
+		}
+
+		return tx.Create(&entities.CalendarToken{
+			UserID:    user.ID,
+			TokenHash: hashCalendarToken(tokenClear),
+		}).Error
+	})
+	if err != nil {
+		return "", errors.NewDBErrorFromError(err)
+	}
+
+	return tokenClear, nil
+}
+
+// Revokes all calendar feed tokens of the user.
+func RevokeCalendarTokens(user *entities.User) *errors.RstError {
+	if res := db.DB.Model(&entities.CalendarToken{}).Where("user_id = ?", user.ID).Update("is_revoked", true); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	return nil
+}
+
+// Returns the user the given calendar feed token belongs to, or nil if the token is invalid or revoked.
+func AuthenticateCalendarToken(token string) *entities.User {
+	if token == "" {
+		return nil
+	}
+
+	calendarToken := &entities.CalendarToken{}
+	if res := db.DB.Where("token_hash = ? AND is_revoked = ?", hashCalendarToken(token), false).First(calendarToken); res.Error != nil {
+		return nil
+	}
+
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", calendarToken.UserID).First(user); res.Error != nil {
+		return nil
+	}
+
+	if CheckUserLoginAllowance(user) != nil {
+		return nil
+	}
+
+	return user
+}
+
+func hashCalendarToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -8,42 +8,76 @@ import (
 )
 
 type decodedToken struct {
+	ID        string
 	Subject   uuid.UUID
+	SessionID uuid.UUID // uuid.Nil for tokens not bound to a session, e.g. activation tokens
+	IssuedAt  int64
 	ExpiresAt int64
 }
 
-func decodeToken(secret string, tokenString string) (*decodedToken, *errors.RstError) {
+// Verifies the token with the key named by its kid header and checks that it was issued for the audience.
+func decodeToken(audience string, tokenString string) (*decodedToken, *errors.RstError) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, ErrInvalidSigningMethod
 		}
 
-		return []byte(secret), nil
-	})
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		key := verificationKey(kid)
+		if key == nil {
+			return nil, ErrInvalidToken
+		}
+		return key, nil
+	}, jwt.WithValidMethods([]string{signingKeyAlgorithm}), jwt.WithAudience(audience), jwt.WithIssuer(tokenIssuer))
 
 	if err != nil {
 		return nil, errors.NewFromError(err)
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		subClaim, ok := claims["sub"]
+		subClaim, ok := claims["sub"].(string)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		expClaim, ok := claims["exp"].(float64)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+
+		iatClaim, ok := claims["iat"].(float64)
 		if !ok {
 			return nil, ErrInvalidToken
 		}
 
-		expClaim, ok := claims["exp"]
+		jtiClaim, ok := claims["jti"].(string)
 		if !ok {
 			return nil, ErrInvalidToken
 		}
 
-		subject, err := uuid.Parse(subClaim.(string))
+		subject, err := uuid.Parse(subClaim)
 		if err != nil {
 			return nil, errors.NewFromError(err)
 		}
 
+		sessionID := uuid.Nil
+		if sidClaim, ok := claims["sid"].(string); ok {
+			sessionID, err = uuid.Parse(sidClaim)
+			if err != nil {
+				return nil, errors.NewFromError(err)
+			}
+		}
+
 		return &decodedToken{
+			ID:        jtiClaim,
 			Subject:   subject,
-			ExpiresAt: int64(expClaim.(float64)),
+			SessionID: sessionID,
+			IssuedAt:  int64(iatClaim),
+			ExpiresAt: int64(expClaim),
 		}, nil
 	} else {
 		return nil, ErrInvalidToken
@@ -34,6 +34,8 @@ func Initialize() {
 		Scopes:       []string{discord.ScopeIdentify},
 		Endpoint:     discord.Endpoint,
 	}
+
+	initializeInteractions()
 }
 
 func GenerateCodeVerifier() (string, error) {
@@ -0,0 +1,44 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"os"
+)
+
+var interactionPublicKey ed25519.PublicKey
+
+func initializeInteractions() {
+	keyHex := os.Getenv("DISCORD_PUBLIC_KEY")
+	if keyHex == "" {
+		log.Println("DISCORD_PUBLIC_KEY not set, interactions endpoint will reject all requests")
+		return
+	}
+
+	key, err := hex.DecodeString(keyHex)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		log.Println("DISCORD_PUBLIC_KEY is not a valid hex encoded ed25519 public key")
+		return
+	}
+
+	interactionPublicKey = ed25519.PublicKey(key)
+}
+
+// Verifies the Ed25519 signature Discord attaches to every interaction request.
+// The signed message is the timestamp header followed by the raw request body.
+func VerifyInteraction(signature string, timestamp string, body []byte) bool {
+	if interactionPublicKey == nil || signature == "" || timestamp == "" {
+		return false
+	}
+
+	sig, err := hex.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+
+	msg := make([]byte, 0, len(timestamp)+len(body))
+	msg = append(msg, timestamp...)
+	msg = append(msg, body...)
+
+	return ed25519.Verify(interactionPublicKey, msg, sig)
+}
@@ -33,6 +33,19 @@ var (
 	ErrEmailDidNotChange       = errors.New(1013, *ErrPackageAuth, 400, "", "Email did not change")
 	ErrInvalidLocale           = errors.New(1014, *ErrPackageAuth, 400, "", "Invalid locale")
 	ErrInvalidOTPCode          = errors.New(1015, *ErrPackageAuth, 400, "", "Invalid OTP code")
+	ErrInvalidCalendarToken    = errors.New(1016, *ErrPackageAuth, 401, "", "Invalid calendar token")
+	ErrInvalidTokenExpiry      = errors.New(1017, *ErrPackageAuth, 400, "", "Invalid token expiry")
+	ErrInvalidScope            = errors.New(1018, *ErrPackageAuth, 400, "", "Invalid scope")
+	ErrInvalidUserCode         = errors.New(1019, *ErrPackageAuth, 400, "", "Invalid user code")
+	ErrDeviceCodeExpired       = errors.New(1020, *ErrPackageAuth, 400, "expired_token", "Device code expired")
+	ErrUnsupportedGrantType    = errors.New(1021, *ErrPackageAuth, 400, "unsupported_grant_type", "Unsupported grant type")
+	ErrInvalidClient           = errors.New(1022, *ErrPackageAuth, 401, "invalid_client", "Invalid client")
+	ErrInvalidGrant            = errors.New(1023, *ErrPackageAuth, 400, "invalid_grant", "Invalid grant")
+	ErrInvalidRedirectUri      = errors.New(1024, *ErrPackageAuth, 400, "invalid_request", "Invalid redirect uri")
+	ErrInvalidCodeChallenge    = errors.New(1025, *ErrPackageAuth, 400, "invalid_request", "Invalid code challenge")
+	ErrUnsupportedResponseType = errors.New(1026, *ErrPackageAuth, 400, "unsupported_response_type", "Unsupported response type")
+	ErrInvalidOAuthScope       = errors.New(1027, *ErrPackageAuth, 400, "invalid_scope", "Invalid scope")
+	ErrInvalidUnlockToken      = errors.New(1028, *ErrPackageAuth, 400, "", "Invalid unlock token")
 
 	ErrInvalidSigningMethod = errors.New(1901, *ErrPackageAuth, 500, "", "Invalid signing method")
 
@@ -48,12 +61,19 @@ var (
 	ErrFidoNameIsMissing       = errors.New(2010, *ErrPackageAuth, 400, "", "Fido name is missing")
 	ErrOTPIsNotSet             = errors.New(2011, *ErrPackageAuth, 400, "", "OTP is not set")
 	ErrOTPIsNotVerified        = errors.New(2012, *ErrPackageAuth, 400, "", "OTP is not verified")
+	ErrFrontierNotLinked       = errors.New(2013, *ErrPackageAuth, 400, "", "Frontier not linked")
+	ErrApiTokenNotFound        = errors.New(2014, *ErrPackageAuth, 404, "", "Api token not found")
+	ErrInfraClientNotFound     = errors.New(2015, *ErrPackageAuth, 404, "", "Infra client not found")
+	ErrOAuthGrantNotFound      = errors.New(2016, *ErrPackageAuth, 404, "", "OAuth grant not found")
+	ErrSessionNotFound         = errors.New(2018, *ErrPackageAuth, 404, "", "Session not found")
 
-	ErrEmailTaken           = errors.New(3001, *ErrPackageAuth, 400, "", "Email taken")
-	ErrUserAlreadyActivated = errors.New(3002, *ErrPackageAuth, 409, "", "User already activated")
-	ErrDiscordAlreadyLinked = errors.New(3003, *ErrPackageAuth, 409, "", "Discord already linked")
-	ErrOTPAlreadySet        = errors.New(3004, *ErrPackageAuth, 409, "", "OTP already set")
-	ErrOTPAlreadyVerified   = errors.New(3005, *ErrPackageAuth, 409, "", "OTP already verified")
+	ErrEmailTaken            = errors.New(3001, *ErrPackageAuth, 400, "", "Email taken")
+	ErrUserAlreadyActivated  = errors.New(3002, *ErrPackageAuth, 409, "", "User already activated")
+	ErrDiscordAlreadyLinked  = errors.New(3003, *ErrPackageAuth, 409, "", "Discord already linked")
+	ErrOTPAlreadySet         = errors.New(3004, *ErrPackageAuth, 409, "", "OTP already set")
+	ErrOTPAlreadyVerified    = errors.New(3005, *ErrPackageAuth, 409, "", "OTP already verified")
+	ErrFrontierAlreadyLinked = errors.New(3006, *ErrPackageAuth, 409, "", "Frontier already linked")
+	ErrInfraClientNameTaken  = errors.New(3007, *ErrPackageAuth, 409, "", "Infra client name taken")
 
 	ErrForbidden                        = errors.New(4000, *ErrPackageAuth, 403, "", "Forbidden")
 	ErrUnauthorized                     = errors.New(4001, *ErrPackageAuth, 401, "", "Unauthorized")
@@ -61,6 +81,11 @@ var (
 	ErrUserOtpWrong                     = errors.New(4003, *ErrPackageAuth, 403, "", "User otp wrong")
 	ErrInvalidCredentials               = errors.NewWithInternalMessage(4004, *ErrPackageAuth, 403, "", "Invalid credentials", "In sentry additional error above might be attached")
 	ErrUserNotFoundOrInvalidCredentials = errors.NewWithInternalMessage(4005, *ErrPackageAuth, 404, "", "User not found or invalid credentials", "In sentry see above error for more details.")
+	ErrTokenAccessNotAllowed            = errors.New(4006, *ErrPackageAuth, 403, "", "Token access not allowed")
+	ErrInsufficientScope                = errors.New(4007, *ErrPackageAuth, 403, "", "Insufficient scope")
+	ErrDevicePairingDenied              = errors.New(4008, *ErrPackageAuth, 400, "access_denied", "Device pairing denied")
+	ErrTooManyAttempts                  = errors.New(4009, *ErrPackageAuth, 429, "", "Too many attempts, try again later")
+	ErrAccountLocked                    = errors.New(4010, *ErrPackageAuth, 423, "", "Account temporarily locked, see your emails to unlock it")
 
 	ErrServer                          = errors.New(5001, *ErrPackageAuth, 500, "", "Internal server error")
 	ErrQuickloginTokenRequestFailed    = errors.NewWithInternalMessage(5002, *ErrPackageAuth, 400, "", "Could not request quicklogin token", "In sentry see above error for more details.")
@@ -75,4 +100,6 @@ var (
 	ErrAbsoluteExpReached   = errors.New(9001, *ErrPackageAuth, 401, "", "Absolute expiration reached")
 	ErrUsedRefreshToken     = errors.New(9002, *ErrPackageAuth, 400, "", "Used refresh token")
 	ErrRegistrationDisabled = errors.New(9003, *ErrPackageAuth, 403, "", "Registration is disabled")
+	ErrAuthorizationPending = errors.New(9004, *ErrPackageAuth, 400, "authorization_pending", "Authorization pending")
+	ErrSlowDown             = errors.New(9005, *ErrPackageAuth, 400, "slow_down", "Polling too fast")
 )
@@ -0,0 +1,115 @@
+package frontier
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/util"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"golang.org/x/oauth2"
+)
+
+const defaultAuthUrl = "https://auth.frontierstore.net"
+
+var LinkingConf *oauth2.Config
+
+var log = logging.Logger{Package: "frontier"}
+
+// Base url of the Frontier auth server, FRONTIER_AUTH_URL allows pointing to a local mock server
+func authUrl() string {
+	url := os.Getenv("FRONTIER_AUTH_URL")
+	if url == "" {
+		url = defaultAuthUrl
+	}
+	return strings.TrimSuffix(url, "/")
+}
+
+func Initialize() {
+	base := authUrl()
+
+	// Frontier issues public clients without secret, PKCE protects the code exchange
+	LinkingConf = &oauth2.Config{
+		RedirectURL: os.Getenv("BACKEND_URL") + "/v1/users/link/frontier/callback",
+		ClientID:    os.Getenv("FRONTIER_CLIENT_ID"),
+		Scopes:      []string{"auth", "capi"},
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   base + "/auth",
+			TokenURL:  base + "/token",
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
+
+func GenerateCodeVerifier() (string, error) {
+	return util.GenerateRandomString(128)
+}
+
+func GetOAuthUrl(conf *oauth2.Config, state string, codeVerifier string) string {
+	sha2 := sha256.New()
+	io.WriteString(sha2, codeVerifier)
+	codeChallenge := base64.RawURLEncoding.EncodeToString(sha2.Sum(nil))
+	return conf.AuthCodeURL(state, oauth2.SetAuthURLParam("code_challenge", codeChallenge), oauth2.SetAuthURLParam("code_challenge_method", "S256"))
+}
+
+// Exchanges the authorization code and fetches the account it belongs to.
+func RetrieveOAuthAccount(conf *oauth2.Config, code string, codeVerifier string) (bool, *oauth2.Token, *FrontierAccount) {
+	token, err := conf.Exchange(context.Background(), code, oauth2.SetAuthURLParam("code_verifier", codeVerifier))
+	if err != nil {
+		log.Printf(err.Error())
+		return false, nil, nil
+	}
+
+	res, err := conf.Client(context.Background(), token).Get(authUrl() + "/me")
+	if err != nil {
+		log.Printf(err.Error())
+		return false, nil, nil
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		log.Printf("Frontier account lookup failed with status %d", res.StatusCode)
+		return false, nil, nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		log.Printf(err.Error())
+		return false, nil, nil
+	}
+
+	account := &FrontierAccount{}
+	if err := jsoniter.Unmarshal(body, account); err != nil || account.CustomerID.String() == "" {
+		log.Printf("Could not decode Frontier account: %v", err)
+		return false, nil, nil
+	}
+
+	return true, token, account
+}
+
+// Refreshes tokens using the linking configuration. Frontier may rotate the refresh token,
+// so the returned token has to be stored again.
+type OAuthRefresher struct {
+	Conf *oauth2.Config
+}
+
+func (r *OAuthRefresher) Refresh(refreshToken string) (*oauth2.Token, error) {
+	if r.Conf == nil {
+		return nil, fmt.Errorf("Frontier OAuth is not initialized")
+	}
+
+	token, err := r.Conf.TokenSource(context.Background(), &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
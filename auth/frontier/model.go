@@ -0,0 +1,9 @@
+package frontier
+
+import jsoniter "github.com/json-iterator/go"
+
+type FrontierAccount struct {
+	// sent as number or string depending on the account
+	CustomerID jsoniter.Number `json:"customer_id"`
+	Email      string          `json:"email"`
+}
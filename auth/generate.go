@@ -1,8 +1,6 @@
 package auth
 
 import (
-	"log"
-	"os"
 	"ruehrstaat-backend/errors"
 	"time"
 
@@ -10,25 +8,45 @@ import (
 	"github.com/google/uuid"
 )
 
-func generatePair(userID uuid.UUID, absoluteExpiration *int64) (TokenPair, *errors.RstError) {
+const (
+	tokenIssuer = "Ruehrstaat Auth"
+
+	// every kind of token has its own audience, so e.g. an activation token can't be used as identity token
+	identityTokenAudience      = "ruehrstaat.org"
+	refreshTokenAudience       = "ruehrstaat.org/refresh"
+	activationTokenAudience    = "Ruehrstaat-Squadron Account Activation"
+	passwordResetTokenAudience = "Ruehrstaat-Squadron Account Passwort Reset"
+	emailChangeTokenAudience   = "Ruehrstaat-Squadron email change"
+	accountUnlockTokenAudience = "Ruehrstaat-Squadron account unlock"
+
+	identityTokenLifetime = time.Hour * 6
+	refreshTokenLifetime  = time.Hour * 24 * 30
+)
+
+// Issues the identity and refresh token of a session, both carry the session ID as "sid" claim.
+func generatePair(userID uuid.UUID, sessionID uuid.UUID, absoluteExpiration *int64) (TokenPair, *errors.RstError) {
 	rexp := int64(0)
 	if absoluteExpiration != nil {
 		rexp = *absoluteExpiration
 	} else {
-		rexp = time.Now().Add(time.Hour * 24 * 30).Unix()
+		rexp = time.Now().Add(refreshTokenLifetime).Unix()
 	}
 
 	if rexp < time.Now().Unix() {
 		return TokenPair{}, ErrAbsoluteExpReached
 	}
 
-	exp := time.Now().Add(time.Hour * 6).Unix()
-	identityToken, err := generateToken(getIdentityTokenSecret(), userID.String(), exp)
+	exp := time.Now().Add(identityTokenLifetime).Unix()
+	identityClaims := tokenClaims(identityTokenAudience, userID.String(), exp)
+	identityClaims["sid"] = sessionID.String()
+	identityToken, err := signToken(identityClaims)
 	if err != nil {
 		return TokenPair{}, err
 	}
 
-	refreshToken, err := generateToken(getRefreshTokenSecret(), userID.String(), rexp)
+	refreshClaims := tokenClaims(refreshTokenAudience, userID.String(), rexp)
+	refreshClaims["sid"] = sessionID.String()
+	refreshToken, err := signToken(refreshClaims)
 	if err != nil {
 		return TokenPair{}, err
 	}
@@ -40,59 +58,40 @@ func generatePair(userID uuid.UUID, absoluteExpiration *int64) (TokenPair, *erro
 	}, nil
 }
 
-func generateToken(secret string, sub string, exp int64) (string, *errors.RstError) {
+func tokenClaims(aud string, sub string, exp int64) jwt.MapClaims {
 	currTime := time.Now().Unix()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss": "Ruehrstaat Auth",
-		"aud": "ruehrstaat.org",
+	return jwt.MapClaims{
+		"iss": tokenIssuer,
+		"aud": aud,
 		"sub": sub,
 		"exp": exp,
 		"iat": currTime,
 		"nbf": currTime,
 		"jti": uuid.New().String(),
-	})
-
-	val, err := token.SignedString([]byte(secret))
-	if err != nil {
-		return "", errors.NewFromError(err)
 	}
-	return val, nil
 }
 
-func generateCustomToken(subject string, aud string, hoursExp int) (string, *errors.RstError) {
-	currTime := time.Now().Unix()
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"iss": "Ruehrstaat Auth",
-		"aud": aud,
-		"sub": subject,
-		"exp": time.Now().Add(time.Hour * time.Duration(hoursExp)).Unix(),
-		"iat": currTime,
-		"nbf": currTime,
-		"jti": uuid.New().String(),
-	})
+func generateToken(aud string, sub string, exp int64) (string, *errors.RstError) {
+	return signToken(tokenClaims(aud, sub, exp))
+}
 
-	val, err := token.SignedString([]byte(getIdentityTokenSecret()))
-	if err != nil {
-		return "", errors.NewFromError(err)
-	}
-	return val, nil
+func generateCustomToken(subject string, aud string, hoursExp int) (string, *errors.RstError) {
+	return generateToken(aud, subject, time.Now().Add(time.Hour*time.Duration(hoursExp)).Unix())
 }
 
-func getIdentityTokenSecret() string {
-	secret, ok := os.LookupEnv("JWT_IDENTITY_SECRET")
-	if !ok {
-		log.Println("WARNING!!! JWT_IDENTITY_SECRET not set, using default value")
+// Signs the claims with the active signing key, the kid header tells verifiers which key to use.
+func signToken(claims jwt.MapClaims) (string, *errors.RstError) {
+	key, rerr := activeSigningKey()
+	if rerr != nil {
+		return "", rerr
 	}
 
-	return secret
-}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.kid
 
-func getRefreshTokenSecret() string {
-	secret, ok := os.LookupEnv("JWT_REFRESH_SECRET")
-	if !ok {
-		log.Println("WARNING!!! JWT_REFRESH_SECRET not set, using default value")
-		return "secret"
+	val, err := token.SignedString(key.key)
+	if err != nil {
+		return "", errors.NewFromError(err)
 	}
-
-	return secret
+	return val, nil
 }
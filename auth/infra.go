@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"log"
+	"net/url"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func AuthenticateInfra(c *gin.Context) *entities.InfraToken {
+	clientId := c.GetHeader("X-RST-Client-Id")
+	if clientId == "" {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil
+	}
+
+	clientSecret := c.GetHeader("X-RST-Client-Secret")
+	if clientSecret == "" {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil
+	}
+
+	infra := &entities.InfraToken{}
+	if res := db.DB.Where("id = ? AND is_revoked = ?", clientId, false).First(infra); res.Error != nil {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil
+	}
+
+	if !checkInfraSecret(infra, clientSecret) {
+		c.JSON(401, gin.H{"error": "Unauthorized"})
+		return nil
+	}
+
+	touchInfraClient(infra)
+
+	return infra
+}
+
+func checkInfraSecret(infra *entities.InfraToken, secret string) bool {
+	if bcrypt.CompareHashAndPassword([]byte(infra.SecretHash), []byte(secret)) == nil {
+		return true
+	}
+
+	if infra.PreviousSecretHash == nil || infra.PreviousSecretExpiresAt == nil || infra.PreviousSecretExpiresAt.Before(time.Now()) {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(*infra.PreviousSecretHash), []byte(secret)) == nil
+}
+
+// Records when the client was last used, at most once a minute to spare the database.
+func touchInfraClient(infra *entities.InfraToken) {
+	now := time.Now()
+	if infra.LastUsedAt != nil && now.Sub(*infra.LastUsedAt) < time.Minute {
+		return
+	}
+
+	infra.LastUsedAt = &now
+	if res := db.DB.Model(infra).UpdateColumn("last_used_at", now); res.Error != nil {
+		log.Println("Could not record usage of infra client", infra.ID, res.Error)
+	}
+}
+
+// Returns the infra client the request was authenticated with.
+func InfraFromContext(c *gin.Context) *entities.InfraToken {
+	infraValue, exists := c.Get("infra")
+	if !exists {
+		return nil
+	}
+	return infraValue.(*entities.InfraToken)
+}
+
+// Declares the scope a route requires from infra clients, has to run after the infra middleware.
+func RequireInfraScope(scope entities.InfraScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		infra := InfraFromContext(c)
+		if infra == nil || !infra.HasScope(scope) {
+			errors.MiddlewareAbortWithError(c, ErrInsufficientScope)
+			return
+		}
+		c.Next()
+	}
+}
+
+func ValidateInfraScopes(scopes []string) *errors.RstError {
+	for _, scope := range scopes {
+		known := false
+		for _, s := range entities.InfraScopes {
+			if string(s) == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+func generateInfraSecret() (string, string, *errors.RstError) {
+	secret, err := util.GenerateRandomString(64)
+	if err != nil {
+		return "", "", errors.NewFromError(err)
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", "", errors.NewFromError(err)
+	}
+
+	return secret, string(hashed), nil
+}
+
+// Creates the infra client, the cleartext secret is only returned here.
+func RegisterInfraClient(infra *entities.InfraToken) (string, *errors.RstError) {
+	infra.Name = strings.TrimSpace(infra.Name)
+
+	if err := ValidateInfraClient(infra); err != nil {
+		return "", err
+	}
+
+	var count int64
+	if res := db.DB.Model(&entities.InfraToken{}).Where("name = ?", infra.Name).Count(&count); res.Error != nil {
+		return "", errors.NewDBErrorFromError(res.Error)
+	}
+	if count > 0 {
+		return "", ErrInfraClientNameTaken
+	}
+
+	secret, hashed, err := generateInfraSecret()
+	if err != nil {
+		return "", err
+	}
+
+	infra.SecretHash = hashed
+	if res := db.DB.Create(infra); res.Error != nil {
+		return "", errors.NewDBErrorFromError(res.Error)
+	}
+
+	return secret, nil
+}
+
+// Validates scopes and oauth settings of the client.
+func ValidateInfraClient(infra *entities.InfraToken) *errors.RstError {
+	if err := ValidateInfraScopes(infra.Scopes); err != nil {
+		return err
+	}
+	if err := ValidateOAuthScopes(infra.OAuthScopes); err != nil {
+		return err
+	}
+
+	// redirect uris have to be absolute and must not contain a fragment (RFC 6749 3.1.2),
+	// native apps may use their own scheme
+	for _, uri := range infra.RedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil || parsed.Scheme == "" || strings.Contains(uri, "#") {
+			return ErrInvalidRedirectUri
+		}
+		if (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host == "" {
+			return ErrInvalidRedirectUri
+		}
+	}
+
+	return nil
+}
+
+func GetInfraClient(id string) (*entities.InfraToken, *errors.RstError) {
+	infra := &entities.InfraToken{}
+	if res := db.DB.Where("id = ?", id).First(infra); res.Error != nil {
+		if res.Error == gorm.ErrRecordNotFound {
+			return nil, ErrInfraClientNotFound
+		}
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return infra, nil
+}
+
+// Replaces the secret of the client. The previous secret stays valid for the grace period,
+// a grace period of 0 invalidates it immediately.
+func RotateInfraSecret(infra *entities.InfraToken, grace time.Duration) (string, *errors.RstError) {
+	secret, hashed, err := generateInfraSecret()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"secret":                     hashed,
+		"secret_rotated_at":          now,
+		"previous_secret_hash":       nil,
+		"previous_secret_expires_at": nil,
+	}
+	if grace > 0 {
+		updates["previous_secret_hash"] = infra.SecretHash
+		updates["previous_secret_expires_at"] = now.Add(grace)
+	}
+
+	if res := db.DB.Model(infra).Updates(updates); res.Error != nil {
+		return "", errors.NewDBErrorFromError(res.Error)
+	}
+
+	infra.SecretHash = hashed
+	infra.SecretRotatedAt = &now
+	infra.PreviousSecretHash = nil
+	infra.PreviousSecretExpiresAt = nil
+	if grace > 0 {
+		previous := updates["previous_secret_hash"].(string)
+		expiresAt := now.Add(grace)
+		infra.PreviousSecretHash = &previous
+		infra.PreviousSecretExpiresAt = &expiresAt
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"log"
+	"math/big"
+	"os"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+	"sync"
+	"time"
+)
+
+// All JWTs are signed with RS256 keys stored encrypted in the database. Every token carries the kid
+// of its key, so keys can be rotated while tokens signed with the previous key stay valid.
+
+const (
+	signingKeyAlgorithm = "RS256"
+	signingKeyBits      = 2048
+
+	// instances pick up keys rotated by another instance after this long
+	keyringReloadInterval = time.Minute * 5
+	// unknown kids trigger a reload, but not more often than this
+	keyringMissReloadInterval = time.Second * 30
+
+	defaultKeyRotationInterval = time.Hour * 24 * 30
+	// has to cover the refresh token lifetime, otherwise sessions end with the rotation
+	defaultKeyGracePeriod = time.Hour * 24 * 30
+)
+
+type signingKey struct {
+	kid         string
+	key         *rsa.PrivateKey
+	verifyUntil *time.Time
+}
+
+var keyring = struct {
+	sync.RWMutex
+	active   *signingKey
+	keys     map[string]*signingKey
+	loadedAt time.Time
+}{keys: map[string]*signingKey{}}
+
+// Rotation interval of the signing key, configured via JWT_KEY_ROTATION_INTERVAL (e.g. "720h").
+func keyRotationInterval() time.Duration {
+	interval, err := time.ParseDuration(os.Getenv("JWT_KEY_ROTATION_INTERVAL"))
+	if err != nil || interval <= 0 {
+		return defaultKeyRotationInterval
+	}
+	return interval
+}
+
+// How long retired keys keep verifying tokens, configured via JWT_KEY_GRACE_PERIOD (e.g. "720h").
+func keyGracePeriod() time.Duration {
+	grace, err := time.ParseDuration(os.Getenv("JWT_KEY_GRACE_PERIOD"))
+	if err != nil || grace <= 0 {
+		return defaultKeyGracePeriod
+	}
+	return grace
+}
+
+// Loads the signing keys and creates the first one if there is none.
+// Panics without key material, tokens can neither be issued nor verified then.
+func InitializeSigningKeys() {
+	if os.Getenv("ENCRYPTION_KEY") == "" {
+		panic("ENCRYPTION_KEY not set, signing keys can not be stored")
+	}
+
+	if err := loadSigningKeys(); err != nil {
+		panic("Signing keys can not be loaded: " + err.Error())
+	}
+
+	if keyring.active == nil {
+		log.Println("No signing key found, generating one")
+		if err := createSigningKey(); err != nil {
+			panic("Signing key can not be created: " + err.Error())
+		}
+		if err := loadSigningKeys(); err != nil {
+			panic("Signing keys can not be loaded: " + err.Error())
+		}
+	}
+
+	if keyring.active == nil {
+		panic("No signing key available")
+	}
+}
+
+func loadSigningKeys() *errors.RstError {
+	stored := []entities.SigningKey{}
+	if res := db.DB.Where("verify_until IS NULL OR verify_until > ?", time.Now()).Order("created_at DESC").Find(&stored); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	var active *signingKey
+	keys := map[string]*signingKey{}
+	for _, s := range stored {
+		key, err := decryptSigningKey(&s)
+		if err != nil {
+			return err
+		}
+		keys[key.kid] = key
+
+		// keys are ordered newest first
+		if active == nil && s.RetiredAt == nil {
+			active = key
+		}
+	}
+
+	keyring.Lock()
+	keyring.active = active
+	keyring.keys = keys
+	keyring.loadedAt = time.Now()
+	keyring.Unlock()
+
+	return nil
+}
+
+func decryptSigningKey(stored *entities.SigningKey) (*signingKey, *errors.RstError) {
+	encoded, err := util.Decrypt(stored.PrivateKey)
+	if err != nil {
+		return nil, errors.NewFromError(err)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.NewFromError(err)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, errors.NewFromError(err)
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidSigningMethod
+	}
+
+	return &signingKey{
+		kid:         stored.Kid,
+		key:         key,
+		verifyUntil: stored.VerifyUntil,
+	}, nil
+}
+
+func createSigningKey() *errors.RstError {
+	key, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return errors.NewFromError(err)
+	}
+
+	privateDer, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return errors.NewFromError(err)
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return errors.NewFromError(err)
+	}
+
+	encrypted, err := util.Encrypt(base64.StdEncoding.EncodeToString(privateDer))
+	if err != nil {
+		return errors.NewFromError(err)
+	}
+
+	thumbprint := sha256.Sum256(publicDer)
+
+	stored := &entities.SigningKey{
+		Kid:        base64.RawURLEncoding.EncodeToString(thumbprint[:])[:16],
+		Algorithm:  signingKeyAlgorithm,
+		PrivateKey: encrypted,
+		PublicKey:  base64.StdEncoding.EncodeToString(publicDer),
+	}
+	if res := db.DB.Create(stored); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	return nil
+}
+
+// Key new tokens are signed with.
+func activeSigningKey() (*signingKey, *errors.RstError) {
+	keyring.RLock()
+	stale := time.Since(keyring.loadedAt) > keyringReloadInterval
+	keyring.RUnlock()
+
+	if stale {
+		if err := loadSigningKeys(); err != nil {
+			// keep signing with the known key, the database might just be unavailable for a moment
+			log.Println("Failed to reload signing keys: " + err.Error())
+		}
+	}
+
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	if keyring.active == nil {
+		return nil, ErrServer
+	}
+	return keyring.active, nil
+}
+
+// Public key for the kid of a token, nil if the key is unknown or no longer accepted.
+func verificationKey(kid string) *rsa.PublicKey {
+	keyring.RLock()
+	key, ok := keyring.keys[kid]
+	sinceLoad := time.Since(keyring.loadedAt)
+	keyring.RUnlock()
+
+	// the key might have been created by another instance since the last load
+	if (!ok && sinceLoad > keyringMissReloadInterval) || sinceLoad > keyringReloadInterval {
+		if err := loadSigningKeys(); err != nil {
+			log.Println("Failed to reload signing keys: " + err.Error())
+		}
+
+		keyring.RLock()
+		key, ok = keyring.keys[kid]
+		keyring.RUnlock()
+	}
+
+	if !ok || (key.verifyUntil != nil && key.verifyUntil.Before(time.Now())) {
+		return nil
+	}
+	return &key.key.PublicKey
+}
+
+// Public keys of all keys tokens are currently accepted for (RFC 7517).
+func SigningKeyJWKS() []map[string]string {
+	keyring.RLock()
+	defer keyring.RUnlock()
+
+	jwks := []map[string]string{}
+	for kid, key := range keyring.keys {
+		if key.verifyUntil != nil && key.verifyUntil.Before(time.Now()) {
+			continue
+		}
+
+		jwks = append(jwks, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": signingKeyAlgorithm,
+			"kid": kid,
+			"n":   base64.RawURLEncoding.EncodeToString(key.key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.key.PublicKey.E)).Bytes()),
+		})
+	}
+	return jwks
+}
+
+// Creates a new signing key once the active one is older than the rotation interval. The previous key keeps
+// verifying tokens for the grace period, keys past their grace period are deleted.
+func RotateSigningKeys() {
+	now := time.Now()
+
+	if res := db.DB.Where("verify_until IS NOT NULL AND verify_until < ?", now).Delete(&entities.SigningKey{}); res.Error != nil {
+		log.Println("Failed to delete expired signing keys: " + res.Error.Error())
+	}
+
+	active := &entities.SigningKey{}
+	res := db.DB.Where("retired_at IS NULL").Order("created_at DESC").Limit(1).Find(active)
+	if res.Error != nil {
+		log.Println("Failed to get active signing key: " + res.Error.Error())
+		return
+	}
+	if res.RowsAffected > 0 && active.CreatedAt.Add(keyRotationInterval()).After(now) {
+		return
+	}
+
+	if err := createSigningKey(); err != nil {
+		log.Println("Failed to create signing key: " + err.Error())
+		return
+	}
+
+	if res.RowsAffected > 0 {
+		verifyUntil := now.Add(keyGracePeriod())
+		if res := db.DB.Model(&entities.SigningKey{}).Where("retired_at IS NULL AND created_at <= ?", active.CreatedAt).Updates(map[string]interface{}{
+			"retired_at":   now,
+			"verify_until": verifyUntil,
+		}); res.Error != nil {
+			log.Println("Failed to retire signing key: " + res.Error.Error())
+		}
+	}
+
+	if err := loadSigningKeys(); err != nil {
+		log.Println("Failed to reload signing keys: " + err.Error())
+		return
+	}
+
+	log.Println("Rotated signing key")
+}
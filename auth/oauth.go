@@ -0,0 +1,459 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// OAuth2 authorization server (RFC 6749) for third party apps, clients are infra clients with registered
+// redirect uris. Only the authorization code flow with PKCE (RFC 7636) is supported, access tokens are
+// api tokens linked to the grant so they are limited by the same scopes.
+
+const (
+	oauthCodeDuration      = time.Minute * 5
+	oauthAccessTokenExpiry = time.Hour
+	oauthRefreshExpiry     = time.Hour * 24 * 30
+)
+
+type AuthorizationRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string // OpenID Connect
+}
+
+type oauthCode struct {
+	ClientID      uuid.UUID `json:"clientId"`
+	UserID        uuid.UUID `json:"userId"`
+	RedirectURI   string    `json:"redirectUri"`
+	Scopes        []string  `json:"scopes"`
+	CodeChallenge string    `json:"codeChallenge"`
+	Nonce         string    `json:"nonce"`
+}
+
+type OAuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	IDToken      string // only for the openid scope
+	ExpiresIn    int
+	Scopes       []string
+	User         *entities.User
+}
+
+// Checks the client, redirect uri, PKCE parameters and requested scopes. Errors about the client or the
+// redirect uri must not be sent to the redirect uri, as it can not be trusted then.
+func ValidateAuthorizationRequest(req *AuthorizationRequest) (*entities.InfraToken, []string, *errors.RstError) {
+	client, err := findOAuthClient(req.ClientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if req.RedirectURI == "" || !client.HasRedirectURI(req.RedirectURI) {
+		return nil, nil, ErrInvalidRedirectUri
+	}
+
+	if req.ResponseType != "code" {
+		return client, nil, ErrUnsupportedResponseType
+	}
+
+	// the challenge is the base64url encoded sha256 of a verifier with 43-128 characters
+	if req.CodeChallengeMethod != "S256" || len(req.CodeChallenge) != 43 {
+		return client, nil, ErrInvalidCodeChallenge
+	}
+
+	scopes, err := parseOAuthScopes(client, req.Scope)
+	if err != nil {
+		return client, nil, err
+	}
+
+	return client, scopes, nil
+}
+
+func findOAuthClient(clientId string) (*entities.InfraToken, *errors.RstError) {
+	if _, err := uuid.Parse(clientId); err != nil {
+		return nil, ErrInvalidClient
+	}
+
+	client := &entities.InfraToken{}
+	res := db.DB.Where("id = ? AND is_revoked = ?", clientId, false).Limit(1).Find(client)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 || len(client.RedirectURIs) == 0 {
+		return nil, ErrInvalidClient
+	}
+
+	return client, nil
+}
+
+// Splits the space separated scope parameter, every scope has to be allowed for the client.
+func parseOAuthScopes(client *entities.InfraToken, scope string) ([]string, *errors.RstError) {
+	scopes := []string{}
+	seen := map[string]bool{}
+	for _, s := range strings.Fields(scope) {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+
+		if !client.AllowsOAuthScope(s) {
+			return nil, ErrInvalidOAuthScope
+		}
+		scopes = append(scopes, s)
+	}
+
+	if len(scopes) == 0 {
+		return nil, ErrInvalidOAuthScope
+	}
+	return scopes, nil
+}
+
+// Whether all requested scopes are part of the granted ones.
+func IsScopeSubset(requested []string, granted []string) bool {
+	for _, r := range requested {
+		found := false
+		for _, g := range granted {
+			if r == g {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Issues an authorization code after the user consented, it can be exchanged once within 5 minutes.
+func AuthorizeOAuthClient(req *AuthorizationRequest, user *entities.User) (string, *errors.RstError) {
+	client, scopes, err := ValidateAuthorizationRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	code, genErr := util.GenerateRandomString(48)
+	if genErr != nil {
+		return "", errors.NewFromError(genErr)
+	}
+
+	cache.BeginSpecificState("oauth_code", code, &oauthCode{
+		ClientID:      client.ID,
+		UserID:        user.ID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+	}, oauthCodeDuration)
+
+	return code, nil
+}
+
+// Appends the parameters to the redirect uri, keeping the query it was registered with.
+func BuildOAuthRedirect(redirectURI string, params map[string]string) string {
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+
+	query := target.Query()
+	for key, value := range params {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	target.RawQuery = query.Encode()
+
+	return target.String()
+}
+
+// Authenticates the client at the token and revocation endpoints. Public clients have no secret,
+// their codes are bound to them by PKCE instead.
+func AuthenticateOAuthClient(clientId string, clientSecret string) (*entities.InfraToken, *errors.RstError) {
+	client, err := findOAuthClient(clientId)
+	if err != nil {
+		return nil, err
+	}
+
+	if client.IsPublicClient {
+		return client, nil
+	}
+
+	if clientSecret == "" || !checkInfraSecret(client, clientSecret) {
+		return nil, ErrInvalidClient
+	}
+
+	touchInfraClient(client)
+
+	return client, nil
+}
+
+func verifyCodeChallenge(verifier string, challenge string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:]) == challenge
+}
+
+func hashOAuthRefreshToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func loadGrantUser(userId uuid.UUID) (*entities.User, *errors.RstError) {
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", userId).First(user); res.Error != nil {
+		return nil, ErrInvalidGrant
+	}
+	if err := CheckUserLoginAllowance(user); err != nil {
+		return nil, ErrInvalidGrant
+	}
+	return user, nil
+}
+
+// Exchanges an authorization code for a new grant with access and refresh token.
+func ExchangeOAuthCode(client *entities.InfraToken, code string, redirectURI string, codeVerifier string) (*OAuthTokens, *errors.RstError) {
+	payload := &oauthCode{}
+	if code == "" || !cache.EndState("oauth_code", code, payload) {
+		return nil, ErrInvalidGrant
+	}
+
+	if payload.ClientID != client.ID || payload.RedirectURI != redirectURI || !verifyCodeChallenge(codeVerifier, payload.CodeChallenge) {
+		return nil, ErrInvalidGrant
+	}
+
+	user, err := loadGrantUser(payload.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, genErr := util.GenerateRandomString(64)
+	if genErr != nil {
+		return nil, errors.NewFromError(genErr)
+	}
+
+	grant := &entities.OAuthGrant{
+		ClientID:         client.ID,
+		UserID:           user.ID,
+		Scopes:           pq.StringArray(payload.Scopes),
+		RefreshTokenHash: hashOAuthRefreshToken(refreshToken),
+		RefreshExpiresAt: time.Now().Add(oauthRefreshExpiry),
+	}
+
+	var accessToken string
+	txErr := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Create(grant); res.Error != nil {
+			return res.Error
+		}
+
+		var err *errors.RstError
+		accessToken, err = issueOAuthAccessToken(tx, client, grant, payload.Scopes)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, errors.NewDBErrorFromError(txErr)
+	}
+
+	tokens := &OAuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(oauthAccessTokenExpiry.Seconds()),
+		Scopes:       payload.Scopes,
+		User:         user,
+	}
+	if hasOAuthScope(payload.Scopes, entities.ScopeOpenID) {
+		if tokens.IDToken, err = issueIDToken(user, client.ID, payload.Scopes, payload.Nonce); err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+func issueOAuthAccessToken(tx *gorm.DB, client *entities.InfraToken, grant *entities.OAuthGrant, scopes []string) (string, *errors.RstError) {
+	return issueApiToken(tx, &entities.ApiToken{
+		UserID:    grant.UserID,
+		Name:      client.Name,
+		ExpiresAt: time.Now().Add(oauthAccessTokenExpiry),
+		Scopes:    pq.StringArray(scopes),
+		GrantID:   &grant.ID,
+	})
+}
+
+// Rotates the refresh token of the grant and issues a new access token, previous access tokens are revoked.
+// The scope can be narrowed down, but not extended beyond what the user consented to.
+func RefreshOAuthGrant(client *entities.InfraToken, refreshToken string, scope string) (*OAuthTokens, *errors.RstError) {
+	grant := &entities.OAuthGrant{}
+	res := db.DB.Where("refresh_token_hash = ? AND client_id = ?", hashOAuthRefreshToken(refreshToken), client.ID).Limit(1).Find(grant)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 || !grant.IsActive() {
+		return nil, ErrInvalidGrant
+	}
+
+	scopes := []string(grant.Scopes)
+	if scope != "" {
+		requested := strings.Fields(scope)
+		if !IsScopeSubset(requested, grant.Scopes) {
+			return nil, ErrInvalidOAuthScope
+		}
+		scopes = requested
+	}
+
+	user, err := loadGrantUser(grant.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, genErr := util.GenerateRandomString(64)
+	if genErr != nil {
+		return nil, errors.NewFromError(genErr)
+	}
+
+	var accessToken string
+	now := time.Now()
+	txErr := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Model(&entities.ApiToken{}).Where("grant_id = ? AND is_revoked = ?", grant.ID, false).Update("is_revoked", true); res.Error != nil {
+			return res.Error
+		}
+
+		if res := tx.Model(grant).Updates(map[string]interface{}{
+			"refresh_token_hash": hashOAuthRefreshToken(newRefreshToken),
+			"refresh_expires_at": now.Add(oauthRefreshExpiry),
+			"last_refreshed_at":  now,
+		}); res.Error != nil {
+			return res.Error
+		}
+
+		var err *errors.RstError
+		accessToken, err = issueOAuthAccessToken(tx, client, grant, scopes)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if txErr != nil {
+		return nil, errors.NewDBErrorFromError(txErr)
+	}
+
+	tokens := &OAuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		ExpiresIn:    int(oauthAccessTokenExpiry.Seconds()),
+		Scopes:       scopes,
+		User:         user,
+	}
+	// refreshed ID tokens carry no nonce, there was no authorization request for them
+	if hasOAuthScope(scopes, entities.ScopeOpenID) {
+		if tokens.IDToken, err = issueIDToken(user, client.ID, scopes, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return tokens, nil
+}
+
+// Revokes the grant together with all access tokens issued for it.
+func RevokeOAuthGrant(grant *entities.OAuthGrant) *errors.RstError {
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Model(grant).Update("is_revoked", true); res.Error != nil {
+			return res.Error
+		}
+		return tx.Model(&entities.ApiToken{}).Where("grant_id = ? AND is_revoked = ?", grant.ID, false).Update("is_revoked", true).Error
+	})
+	if err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+	return nil
+}
+
+// Token revocation (RFC 7009). Refresh tokens revoke the whole grant, access tokens only themselves.
+// Unknown tokens are no error, the client can not do anything about them anyway.
+func RevokeOAuthToken(client *entities.InfraToken, token string) *errors.RstError {
+	grant := &entities.OAuthGrant{}
+	res := db.DB.Where("refresh_token_hash = ? AND client_id = ?", hashOAuthRefreshToken(token), client.ID).Limit(1).Find(grant)
+	if res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return RevokeOAuthGrant(grant)
+	}
+
+	accessToken := authenticateOAuthAccessToken(token)
+	if accessToken == nil {
+		return nil
+	}
+
+	var count int64
+	if res := db.DB.Model(&entities.OAuthGrant{}).Where("id = ? AND client_id = ?", accessToken.GrantID, client.ID).Count(&count); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return RevokeAPIToken(accessToken)
+}
+
+// Returns the opaque access token of an "Authorization: Bearer" header. Session tokens are JWTs
+// and always contain dots, so both can share the header.
+func oauthBearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+
+	token := strings.TrimPrefix(header, "Bearer ")
+	if len(token) != 64 || strings.Contains(token, ".") {
+		return ""
+	}
+	return token
+}
+
+// Whether the request carries api token credentials, either the X-RST headers or an oauth access token.
+func HasApiTokenCredentials(c *gin.Context) bool {
+	return c.GetHeader("X-RST-User-Id") != "" || oauthBearerToken(c) != ""
+}
+
+func authenticateOAuthAccessToken(token string) *entities.ApiToken {
+	if len(token) != 64 {
+		return nil
+	}
+
+	candidates := []entities.ApiToken{}
+	if res := db.DB.Where("prefix = ? AND grant_id IS NOT NULL AND is_revoked = ? AND expires_at > ?", token[:8], false, time.Now()).Find(&candidates); res.Error != nil {
+		return nil
+	}
+
+	for i := range candidates {
+		if bcrypt.CompareHashAndPassword([]byte(candidates[i].Token), []byte(token)) == nil {
+			return &candidates[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"os"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// OpenID Connect on top of the oauth authorization server. ID tokens are signed with the same rotating
+// RS256 keys as all other tokens, relying parties verify them with the published JWKS.
+
+const oidcIdTokenExpiry = time.Hour
+
+func OIDCIssuer() string {
+	return os.Getenv("BACKEND_URL")
+}
+
+func hasOAuthScope(scopes []string, scope entities.ApiTokenScope) bool {
+	for _, s := range scopes {
+		if s == string(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims about the user released for the granted scopes, used for ID tokens and the userinfo endpoint.
+func OIDCUserClaims(user *entities.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{
+		"sub": user.ID.String(),
+	}
+
+	if hasOAuthScope(scopes, entities.ScopeProfile) {
+		claims["nickname"] = user.Nickname
+		claims["cmdrName"] = user.CmdrName
+	}
+	if hasOAuthScope(scopes, entities.ScopeEmail) {
+		claims["email"] = user.Email
+		claims["email_verified"] = user.IsActivated
+	}
+
+	return claims
+}
+
+// Issues an ID token for the client, the nonce of the authorization request is passed through
+// so the client can detect replayed tokens.
+func issueIDToken(user *entities.User, clientId uuid.UUID, scopes []string, nonce string) (string, *errors.RstError) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss": OIDCIssuer(),
+		"aud": clientId.String(),
+		"exp": now.Add(oidcIdTokenExpiry).Unix(),
+		"iat": now.Unix(),
+	}
+	for key, value := range OIDCUserClaims(user, scopes) {
+		claims[key] = value
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+
+	return signToken(claims)
+}
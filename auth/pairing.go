@@ -0,0 +1,270 @@
+package auth
+
+import (
+	"os"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/util"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device authorization grant (RFC 8628) used to pair connector clients like the EDMC plugin.
+// The client requests a device and a user code, the user confirms the user code in the web UI
+// and the client polls with the device code until it receives its api token.
+
+const (
+	DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	devicePairingDuration = time.Minute * 10
+	devicePairingInterval = time.Second * 5
+	pairedTokenLifetime   = time.Hour * 24 * 365
+
+	devicePairingPending  = "pending"
+	devicePairingApproved = "approved"
+	devicePairingDenied   = "denied"
+)
+
+type devicePairing struct {
+	DeviceCode string     `json:"deviceCode"`
+	UserCode   string     `json:"userCode"`
+	ClientName string     `json:"clientName"`
+	Status     string     `json:"status"`
+	UserID     uuid.UUID  `json:"userId"`
+	Scopes     []string   `json:"scopes"`
+	Interval   int        `json:"interval"` // seconds
+	LastPollAt *time.Time `json:"lastPollAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+}
+
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationUri         string `json:"verification_uri"`
+	VerificationUriComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// Pending pairing as shown to the user confirming it.
+type DevicePairingInfo struct {
+	UserCode   string    `json:"userCode"`
+	ClientName string    `json:"clientName"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+func RequestDevicePairing(clientName string) (*DeviceAuthorization, *errors.RstError) {
+	clientName = strings.TrimSpace(clientName)
+	if clientName == "" {
+		clientName = "Connector"
+	}
+	if len(clientName) > 64 {
+		clientName = clientName[:64]
+	}
+
+	deviceCode, err := util.GenerateRandomString(48)
+	if err != nil {
+		return nil, errors.NewFromError(err)
+	}
+
+	var userCode string
+	count := 0
+	for {
+		userCode, err = util.GenerateUserCode(8)
+		if err != nil {
+			return nil, errors.NewFromError(err)
+		}
+
+		if !cache.HasState("device_pairing_code", userCode) {
+			break
+		}
+		count += 1
+		if count > 10 {
+			return nil, ErrServer
+		}
+	}
+
+	pairing := &devicePairing{
+		DeviceCode: deviceCode,
+		UserCode:   userCode,
+		ClientName: clientName,
+		Status:     devicePairingPending,
+		Interval:   int(devicePairingInterval.Seconds()),
+		ExpiresAt:  time.Now().Add(devicePairingDuration),
+	}
+
+	cache.BeginSpecificState("device_pairing", deviceCode, pairing, devicePairingDuration)
+	cache.BeginSpecificState("device_pairing_code", userCode, deviceCode, devicePairingDuration)
+
+	displayCode := FormatUserCode(userCode)
+	return &DeviceAuthorization{
+		DeviceCode:              deviceCode,
+		UserCode:                displayCode,
+		VerificationUri:         os.Getenv("FRONTEND_URL") + "/device",
+		VerificationUriComplete: os.Getenv("FRONTEND_URL") + "/device?code=" + displayCode,
+		ExpiresIn:               int(devicePairingDuration.Seconds()),
+		Interval:                pairing.Interval,
+	}, nil
+}
+
+// Splits the user code in two halves for display, e.g. "BDFG-HJKL".
+func FormatUserCode(userCode string) string {
+	return userCode[:4] + "-" + userCode[4:]
+}
+
+// Users might type the code in lower case, with or without the dash.
+func normalizeUserCode(userCode string) string {
+	userCode = strings.ToUpper(userCode)
+	userCode = strings.ReplaceAll(userCode, "-", "")
+	return strings.ReplaceAll(userCode, " ", "")
+}
+
+func findPendingPairing(userCode string) (*devicePairing, *errors.RstError) {
+	var deviceCode string
+	if !cache.GetState("device_pairing_code", normalizeUserCode(userCode), &deviceCode) {
+		return nil, ErrInvalidUserCode
+	}
+
+	pairing := &devicePairing{}
+	if !cache.GetState("device_pairing", deviceCode, pairing) || pairing.Status != devicePairingPending {
+		return nil, ErrInvalidUserCode
+	}
+
+	return pairing, nil
+}
+
+func savePairing(pairing *devicePairing) {
+	remaining := time.Until(pairing.ExpiresAt)
+	if remaining <= 0 {
+		return
+	}
+	cache.BeginSpecificState("device_pairing", pairing.DeviceCode, pairing, remaining)
+}
+
+func GetDevicePairing(userCode string) (*DevicePairingInfo, *errors.RstError) {
+	pairing, err := findPendingPairing(userCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DevicePairingInfo{
+		UserCode:   FormatUserCode(pairing.UserCode),
+		ClientName: pairing.ClientName,
+		ExpiresAt:  pairing.ExpiresAt,
+	}, nil
+}
+
+// Confirms the pairing, the client receives a connector token for the given carriers on its next poll.
+func ApproveDevicePairing(userCode string, user *entities.User, carrierIds []uuid.UUID) *errors.RstError {
+	scopes := []string{}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range carrierIds {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		scopes = append(scopes, string(entities.ScopeCarrierConnector)+":"+id.String())
+	}
+
+	if err := ValidateScopes(user, user, scopes); err != nil {
+		return err
+	}
+
+	lock := cache.NewLock("device_pairing:"+normalizeUserCode(userCode), nil, nil)
+	if err := lock.Lock(); err != nil {
+		return errors.NewFromError(err)
+	}
+	defer lock.Unlock()
+
+	pairing, err := findPendingPairing(userCode)
+	if err != nil {
+		return err
+	}
+
+	pairing.Status = devicePairingApproved
+	pairing.UserID = user.ID
+	pairing.Scopes = scopes
+	savePairing(pairing)
+
+	return nil
+}
+
+func DenyDevicePairing(userCode string) *errors.RstError {
+	lock := cache.NewLock("device_pairing:"+normalizeUserCode(userCode), nil, nil)
+	if err := lock.Lock(); err != nil {
+		return errors.NewFromError(err)
+	}
+	defer lock.Unlock()
+
+	pairing, err := findPendingPairing(userCode)
+	if err != nil {
+		return err
+	}
+
+	pairing.Status = devicePairingDenied
+	savePairing(pairing)
+
+	return nil
+}
+
+// Polled by the client with its device code. Returns the api token once the user approved the pairing,
+// the pairing ends with the first successful or denied poll.
+func PollDevicePairing(deviceCode string) (*entities.ApiToken, string, *errors.RstError) {
+	pairing := &devicePairing{}
+	if !cache.GetState("device_pairing", deviceCode, pairing) {
+		return nil, "", ErrDeviceCodeExpired
+	}
+
+	lock := cache.NewLock("device_pairing:"+pairing.UserCode, nil, nil)
+	if err := lock.Lock(); err != nil {
+		return nil, "", errors.NewFromError(err)
+	}
+	defer lock.Unlock()
+
+	// re-read, the state might have changed while waiting for the lock
+	if !cache.GetState("device_pairing", deviceCode, pairing) {
+		return nil, "", ErrDeviceCodeExpired
+	}
+
+	switch pairing.Status {
+	case devicePairingDenied:
+		endDevicePairing(pairing)
+		return nil, "", ErrDevicePairingDenied
+
+	case devicePairingApproved:
+		endDevicePairing(pairing)
+
+		user := &entities.User{}
+		if res := db.DB.Where("id = ?", pairing.UserID).First(user); res.Error != nil {
+			return nil, "", errors.NewDBErrorFromError(res.Error)
+		}
+		if err := CheckUserLoginAllowance(user); err != nil {
+			return nil, "", ErrDevicePairingDenied
+		}
+
+		return RegisterAPIToken(user, pairing.ClientName, time.Now().Add(pairedTokenLifetime), pairing.Scopes)
+	}
+
+	now := time.Now()
+	tooFast := pairing.LastPollAt != nil && now.Sub(*pairing.LastPollAt) < time.Duration(pairing.Interval)*time.Second
+	pairing.LastPollAt = &now
+	if tooFast {
+		// clients have to increase their interval by 5 seconds on slow_down
+		pairing.Interval += 5
+		savePairing(pairing)
+		return nil, "", ErrSlowDown
+	}
+	savePairing(pairing)
+
+	return nil, "", ErrAuthorizationPending
+}
+
+func endDevicePairing(pairing *devicePairing) {
+	var deviceCode string
+	cache.EndState("device_pairing_code", pairing.UserCode, &deviceCode)
+	cache.EndState("device_pairing", pairing.DeviceCode, pairing)
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Revoked tokens are denylisted in redis by their jti until they expire anyway. To end all sessions of a user
+// the user's TokensValidAfter is moved forward instead, which rejects every token issued before it.
+
+// Rejects the token until it expires.
+func denyToken(decoded *decodedToken) {
+	remaining := time.Until(time.Unix(decoded.ExpiresAt, 0))
+	if remaining <= 0 {
+		return
+	}
+
+	cache.BeginSpecificState("token_denylist", decoded.ID, true, remaining)
+}
+
+func isTokenDenied(decoded *decodedToken) bool {
+	return cache.HasState("token_denylist", decoded.ID)
+}
+
+// Whether the token is still accepted for the user, i.e. neither denylisted nor issued before the user's sessions were ended.
+func isTokenActive(decoded *decodedToken, user *entities.User) bool {
+	// iat only has second precision, within the second of the revocation only sessions started after it are accepted
+	if user.TokensValidAfter != nil && decoded.IssuedAt <= user.TokensValidAfter.Unix() {
+		if decoded.IssuedAt < user.TokensValidAfter.Unix() || !isSessionStartedAfter(decoded.SessionID, *user.TokensValidAfter) {
+			return false
+		}
+	}
+
+	if decoded.SessionID != uuid.Nil && isSessionDenied(decoded.SessionID) {
+		return false
+	}
+
+	return !isTokenDenied(decoded)
+}
+
+// Whether the session was created after the time, e.g. the one issued right after a password change.
+func isSessionStartedAfter(sessionID uuid.UUID, after time.Time) bool {
+	if sessionID == uuid.Nil {
+		return false
+	}
+
+	res := db.DB.Where("id = ? AND created_at > ?", sessionID, after).Limit(1).Find(&entities.RefreshToken{})
+	return res.Error == nil && res.RowsAffected > 0
+}
+
+// Denylists the identity token, e.g. the one presented on logout.
+func RevokeIdentityToken(identityToken string) *errors.RstError {
+	decoded, err := decodeToken(identityTokenAudience, identityToken)
+	if err != nil {
+		return err
+	}
+
+	denyToken(decoded)
+	return nil
+}
+
+// Ends all sessions of the user, used on logout from all devices, bans, password resets and by admins.
+func RevokeUserTokens(userID uuid.UUID) *errors.RstError {
+	if res := db.DB.Model(&entities.User{}).Where("id = ?", userID).Update("tokens_valid_after", time.Now()); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	if res := db.DB.Where("user_id = ?", userID).Delete(&entities.RefreshToken{}); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Returns the api token the request was authenticated with, nil for regular sessions.
+func ApiTokenFromContext(c *gin.Context) *entities.ApiToken {
+	tokenValue, exists := c.Get("token")
+	if !exists {
+		return nil
+	}
+	return tokenValue.(*entities.ApiToken)
+}
+
+// Authenticates the api token headers or oauth access token and loads the user the token acts for, banned users are rejected.
+func AuthenticateApiTokenUser(c *gin.Context) (*entities.User, *entities.ApiToken) {
+	var token *entities.ApiToken
+	if bearer := oauthBearerToken(c); bearer != "" && c.GetHeader("X-RST-User-Id") == "" {
+		token = authenticateOAuthAccessToken(bearer)
+		if token == nil {
+			c.JSON(401, gin.H{"error": "Unauthorized"})
+			return nil, nil
+		}
+		touchApiToken(token)
+	} else {
+		token = AuthenticateApiToken(c)
+	}
+	if token == nil {
+		return nil, nil
+	}
+
+	user := &entities.User{}
+	if res := db.DB.Where("id = ?", &token.UserID).First(user); res.Error != nil {
+		return nil, nil
+	}
+
+	// tokens of banned users are kept, so they work again once the ban is lifted
+	if user.IsBanned {
+		return nil, nil
+	}
+
+	return user, token
+}
+
+// Declares the scope a route requires from api tokens. Requests authenticated with a session are not affected,
+// so it has to run after the middleware storing the token in the context.
+func RequireScope(scope entities.ApiTokenScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := ApiTokenFromContext(c)
+		if token != nil && !token.HasScope(scope) {
+			errors.MiddlewareAbortWithError(c, ErrInsufficientScope)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Validates the user scopes an oauth client may request, resources can not be requested through oauth.
+func ValidateOAuthScopes(scopes []string) *errors.RstError {
+	known := append(append([]entities.ApiTokenScope{}, entities.ApiTokenScopes...), entities.OIDCScopes...)
+	for _, scope := range scopes {
+		found := false
+		for _, s := range known {
+			if string(s) == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ErrInvalidScope
+		}
+	}
+	return nil
+}
+
+// Validates requested scope entries. Users may restrict scopes to their own carriers,
+// access to other carriers or all carriers can only be granted by admins.
+func ValidateScopes(current *entities.User, user *entities.User, scopes []string) *errors.RstError {
+	if len(scopes) == 0 {
+		return ErrInvalidScope
+	}
+
+	carrierIds := []uuid.UUID{}
+	for _, entry := range scopes {
+		scope, resource := entities.ParseScopeEntry(entry)
+
+		known := false
+		for _, s := range entities.ApiTokenScopes {
+			if s == scope {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return ErrInvalidScope
+		}
+
+		if resource == "" {
+			continue
+		}
+		if !scope.IsCarrierScope() {
+			return ErrInvalidScope
+		}
+
+		if resource == entities.AllCarriers {
+			if !current.IsAdmin {
+				return ErrTokenAccessNotAllowed
+			}
+			continue
+		}
+
+		id, err := uuid.Parse(resource)
+		if err != nil {
+			return ErrInvalidScope
+		}
+		carrierIds = append(carrierIds, id)
+	}
+
+	if len(carrierIds) == 0 || current.IsAdmin {
+		return nil
+	}
+
+	unique := map[uuid.UUID]bool{}
+	for _, id := range carrierIds {
+		unique[id] = true
+	}
+
+	var owned int64
+	if res := db.DB.Model(&entities.Carrier{}).Where("id IN ? AND owner_id = ?", carrierIds, user.ID).Count(&owned); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	if int(owned) != len(unique) {
+		return ErrTokenAccessNotAllowed
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"log"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/mailer"
+	"ruehrstaat-backend/mailer/mails"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Every login creates a session, logging in on another device leaves the existing sessions alone.
+// Ending a session deletes its refresh token and denylists its ID, which rejects the identity tokens
+// issued for it until they expire.
+
+const (
+	LoginMethodPassword   = "password"
+	LoginMethodDiscord    = "discord"
+	LoginMethodFido2      = "fido2"
+	LoginMethodQuicklogin = "quicklogin"
+
+	maxUserAgentLength = 512
+)
+
+// Device information shown in the session listing.
+type SessionMetadata struct {
+	LoginMethod string
+	IP          string
+	UserAgent   string
+}
+
+func NewSessionMetadata(c *gin.Context, loginMethod string) SessionMetadata {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return SessionMetadata{
+		LoginMethod: loginMethod,
+		IP:          c.ClientIP(),
+		UserAgent:   userAgent,
+	}
+}
+
+func createSession(userID uuid.UUID, metadata SessionMetadata) (*TokenPair, *errors.RstError) {
+	sessionID := uuid.New()
+	now := time.Now()
+	expiresAt := now.Add(refreshTokenLifetime)
+
+	absoluteExpiration := expiresAt.Unix()
+	tokenPair, err := generatePair(userID, sessionID, &absoluteExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	session := &entities.RefreshToken{
+		ID:          sessionID,
+		UserID:      userID,
+		Token:       tokenPair.RefreshToken,
+		LoginMethod: metadata.LoginMethod,
+		IP:          metadata.IP,
+		UserAgent:   metadata.UserAgent,
+		CreatedAt:   now,
+		LastUsedAt:  now,
+		ExpiresAt:   expiresAt,
+	}
+	if res := db.DB.Create(session); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+
+	return &tokenPair, nil
+}
+
+func denySession(sessionID uuid.UUID) {
+	cache.BeginSpecificState("session_denylist", sessionID.String(), true, identityTokenLifetime)
+}
+
+func isSessionDenied(sessionID uuid.UUID) bool {
+	return cache.HasState("session_denylist", sessionID.String())
+}
+
+func endSession(session *entities.RefreshToken) *errors.RstError {
+	if res := db.DB.Where("id = ?", session.ID).Delete(&entities.RefreshToken{}); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	denySession(session.ID)
+	return nil
+}
+
+// Ends the session an already rotated refresh token was presented for. Either the token was stolen or the
+// thief already refreshed with it, so the whole token family goes and the user is alerted.
+func handleRefreshTokenReuse(user *entities.User, session *entities.RefreshToken, metadata SessionMetadata) *errors.RstError {
+	if err := endSession(session); err != nil {
+		return err
+	}
+
+	log.Println("Refresh token reuse detected for session", session.ID, "of user", user.ID)
+
+	event := &entities.SecurityEvent{
+		UserID:    user.ID,
+		Type:      entities.SecurityEventRefreshTokenReuse,
+		IP:        metadata.IP,
+		UserAgent: metadata.UserAgent,
+		Details:   "Session " + session.ID.String() + " (" + session.LoginMethod + " login from " + session.IP + ") ended",
+	}
+	if res := db.DB.Create(event); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	if err := mailer.SendMailGraceful(user.Email, mails.SessionReuseMail{
+		Nickname:  user.Nickname,
+		IP:        metadata.IP,
+		UserAgent: metadata.UserAgent,
+		At:        event.CreatedAt,
+	}, user.Locale); err != nil {
+		// the session is ended either way
+		log.Println("Failed to send session reuse mail to user", user.ID)
+	}
+
+	return nil
+}
+
+// Session the identity token of the request was issued for, uuid.Nil if unknown.
+func SessionIDFromContext(c *gin.Context) uuid.UUID {
+	if sessionID, exists := c.Get("sessionId"); exists {
+		return sessionID.(uuid.UUID)
+	}
+	return uuid.Nil
+}
+
+func GetSessions(userID uuid.UUID) ([]entities.RefreshToken, *errors.RstError) {
+	sessions := []entities.RefreshToken{}
+	if res := db.DB.Where("user_id = ? AND expires_at > ?", userID, time.Now()).Order("last_used_at DESC").Find(&sessions); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return sessions, nil
+}
+
+// Deletes sessions past their absolute expiration, their refresh tokens are rejected anyway. Run by the cron system.
+func DeleteExpiredSessions() {
+	res := db.DB.Where("expires_at <= ?", time.Now()).Delete(&entities.RefreshToken{})
+	if res.Error != nil {
+		log.Println("Failed to delete expired sessions: " + res.Error.Error())
+		return
+	}
+	if res.RowsAffected > 0 {
+		log.Println("Deleted", res.RowsAffected, "expired sessions")
+	}
+}
+
+func RevokeSession(userID uuid.UUID, sessionID uuid.UUID) *errors.RstError {
+	session := &entities.RefreshToken{}
+	res := db.DB.Where("id = ? AND user_id = ?", sessionID, userID).Limit(1).Find(session)
+	if res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return endSession(session)
+}
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"log"
 	"ruehrstaat-backend/db"
 	"ruehrstaat-backend/db/entities"
 	"ruehrstaat-backend/errors"
@@ -8,8 +9,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type TokenPair struct {
@@ -18,28 +20,6 @@ type TokenPair struct {
 	ExpiresAt    int64  `json:"expiresAt"` // expiry unix timestamp
 }
 
-func AuthenticateInfra(c *gin.Context) *entities.InfraToken {
-	clientId := c.GetHeader("X-RST-Client-Id")
-	if clientId == "" {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
-		return nil
-	}
-
-	clientSecret := c.GetHeader("X-RST-Client-Secret")
-	if clientSecret == "" {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
-		return nil
-	}
-
-	infra := &entities.InfraToken{}
-	if res := db.DB.Where("id = ? AND secret = ?", clientId, clientSecret).First(infra); res.Error != nil {
-		c.JSON(401, gin.H{"error": "Unauthorized"})
-		return nil
-	}
-
-	return infra
-}
-
 func AuthenticateApiToken(c *gin.Context) *entities.ApiToken {
 	userid := c.GetHeader("X-RST-User-Id")
 	if userid == "" {
@@ -79,9 +59,24 @@ func AuthenticateApiToken(c *gin.Context) *entities.ApiToken {
 		return nil
 	}
 
+	touchApiToken(apiToken)
+
 	return apiToken
 }
 
+// Records when the token was last used, at most once a minute to spare the database.
+func touchApiToken(token *entities.ApiToken) {
+	now := time.Now()
+	if token.LastUsedAt != nil && now.Sub(*token.LastUsedAt) < time.Minute {
+		return
+	}
+
+	token.LastUsedAt = &now
+	if res := db.DB.Model(token).UpdateColumn("last_used_at", now); res.Error != nil {
+		log.Println("Could not record usage of api token", token.ID, res.Error)
+	}
+}
+
 func checkTokenExpired(token *entities.ApiToken) bool {
 	if token.IsRevoked {
 		return true
@@ -97,33 +92,52 @@ func checkTokenExpired(token *entities.ApiToken) bool {
 	return token.IsRevoked
 }
 
-func RegisterAPIToken(user *entities.User) (*entities.ApiToken, string, *errors.RstError) {
+// Creates a new api token with the given scope entries, the cleartext token is only returned here.
+func RegisterAPIToken(user *entities.User, name string, expiresAt time.Time, scopes []string) (*entities.ApiToken, string, *errors.RstError) {
+	apiToken := &entities.ApiToken{
+		UserID:    user.ID,
+		Name:      name,
+		ExpiresAt: expiresAt,
+		IsRevoked: false,
+		Scopes:    pq.StringArray(scopes),
+	}
+
+	tokenClear, err := issueApiToken(db.DB, apiToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiToken, tokenClear, nil
+}
+
+// Generates the secret of the token and stores it, returns the cleartext token.
+func issueApiToken(tx *gorm.DB, apiToken *entities.ApiToken) (string, *errors.RstError) {
 	// generate 64 char token
 	tokenClear, err := util.GenerateRandomString(64)
 	if err != nil {
-		return nil, "", errors.NewFromError(err)
+		return "", errors.NewFromError(err)
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(tokenClear), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, "", errors.NewFromError(err)
+		return "", errors.NewFromError(err)
 	}
 
-	apiToken := &entities.ApiToken{
-		UserID:             user.ID,
-		Token:              string(hashed),
-		Prefix:             tokenClear[:8],
-		ExpiresAt:          time.Now().AddDate(1, 0, 0),
-		IsRevoked:          false,
-		HasFullWriteAccess: false,
-		HasFullReadAccess:  false,
-		HasReadAccessTo:    []uuid.UUID{},
-		HasWriteAccessTo:   []uuid.UUID{},
-	}
+	apiToken.Token = string(hashed)
+	apiToken.Prefix = tokenClear[:8]
 
-	if res := db.DB.Create(apiToken); res.Error != nil {
-		return nil, "", errors.NewDBErrorFromError(res.Error)
+	if res := tx.Create(apiToken); res.Error != nil {
+		return "", errors.NewDBErrorFromError(res.Error)
 	}
 
-	return apiToken, tokenClear, nil
+	return tokenClear, nil
+}
+
+// Revokes the token, it is kept so it still shows up in the token history.
+func RevokeAPIToken(token *entities.ApiToken) *errors.RstError {
+	token.IsRevoked = true
+	if res := db.DB.Model(token).Update("is_revoked", true); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	return nil
 }
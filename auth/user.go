@@ -67,7 +67,7 @@ func Register(email string, password string, nickname string, cmdrName string, a
 // The activation token is used to activate the user's account.
 // It is sent to the user's email address.
 func GenerateActivationToken(user *entities.User) *errors.RstError {
-	token, err := generateCustomToken(user.ID.String(), "Ruehrstaat-Squadron Account Activation", 72)
+	token, err := generateCustomToken(user.ID.String(), activationTokenAudience, 72)
 	if err != nil {
 		return errors.NewFromError(err)
 	}
@@ -95,7 +95,7 @@ func ActivateAccount(userID uuid.UUID, token string) *errors.RstError {
 		return errors.NewFromError(err)
 	}
 
-	decoded, err := decodeToken(getIdentityTokenSecret(), unescaped)
+	decoded, err := decodeToken(activationTokenAudience, unescaped)
 	if err != nil {
 		return ErrInvalidActivationToken
 	}
@@ -129,7 +129,7 @@ func ActivateAccount(userID uuid.UUID, token string) *errors.RstError {
 }
 
 func GenerateResetPasswordToken(user *entities.User) *errors.RstError {
-	token, err := generateCustomToken(user.ID.String(), "Ruehrstaat-Squadron Account Passwort Reset", 1)
+	token, err := generateCustomToken(user.ID.String(), passwordResetTokenAudience, 1)
 	if err != nil {
 		return errors.NewFromError(err)
 	}
@@ -155,7 +155,7 @@ func ResetPassword(userID uuid.UUID, token string, password string, otp *string)
 		return errors.NewFromError(err)
 	}
 
-	decoded, err := decodeToken(getIdentityTokenSecret(), unescaped)
+	decoded, err := decodeToken(passwordResetTokenAudience, unescaped)
 	if err != nil {
 		println("Error decoding token:")
 		println(err.Error())
@@ -206,45 +206,51 @@ func ResetPassword(userID uuid.UUID, token string, password string, otp *string)
 		return errors.NewDBErrorFromError(res.Error)
 	}
 
-	return nil
+	// whoever knew the old password might still have a session
+	return RevokeUserTokens(user.ID)
 }
 
-func ChangePassword(user *entities.User, oldPassword string, newPassword string, otp *string) *errors.RstError {
+func ChangePassword(user *entities.User, oldPassword string, newPassword string, otp *string, metadata SessionMetadata) (*TokenPair, *errors.RstError) {
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
-		return ErrInvalidCredentials
+		return nil, ErrInvalidCredentials
 	}
 
 	if user.HasTwoFactor() {
 		if otp == nil {
-			return ErrUserOtpMissing
+			return nil, ErrUserOtpMissing
 		}
 
 		if !totp.Validate(*otp, *user.OtpSecret) {
 			if err := TryBackupCodes(user, otp); err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return errors.NewFromError(err)
+		return nil, errors.NewFromError(err)
 	}
 
 	user.Password = string(hashed)
 
 	if res := db.DB.Save(user); res.Error != nil {
-		return errors.NewFromError(res.Error)
+		return nil, errors.NewFromError(res.Error)
 	}
 
-	return nil
+	// whoever knew the old password may still be logged in somewhere, only the device changing it stays logged in
+	if err := RevokeUserTokens(user.ID); err != nil {
+		return nil, err
+	}
+
+	return createSession(user.ID, metadata)
 }
 
 // Generates a new 3-day expiration activation token for the given user.
 // The activation token is used to activate the user's account.
 // It is sent to the user's email address.
 func GenerateEmailChangeToken(user *entities.User) *errors.RstError {
-	token, err := generateCustomToken(user.ID.String(), "Ruehrstaat-Squadron email change", 72)
+	token, err := generateCustomToken(user.ID.String(), emailChangeTokenAudience, 72)
 	if err != nil {
 		return err
 	}
@@ -305,7 +311,7 @@ func ChangeEmail(user *entities.User, token string) *errors.RstError {
 		return errors.NewFromError(err)
 	}
 
-	decoded, err := decodeToken(getIdentityTokenSecret(), unescaped)
+	decoded, err := decodeToken(emailChangeTokenAudience, unescaped)
 	if err != nil {
 		return ErrInvalidEmailChangeToken
 	}
@@ -57,6 +57,25 @@ func BeginSpecificState(category string, state string, payload any, duration tim
 	Redis.Set(context.Background(), "state:"+category+":"+state, string(data), duration)
 }
 
+// Reads a cached state without ending it.
+func GetState(category string, state string, payload any) bool {
+	data, err := Redis.Get(context.Background(), "state:"+category+":"+state).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return false
+		}
+
+		panic(err)
+	}
+
+	err = jsoniter.Unmarshal([]byte(data), payload)
+	if err != nil {
+		panic(err)
+	}
+
+	return true
+}
+
 // Deletes a cached state.
 func EndState(category string, state string, payload any) bool {
 	data, err := Redis.Get(context.Background(), "state:"+category+":"+state).Result()
@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Sliding window counters, each entry is a member of a sorted set scored by the unix time in milliseconds it was added.
+
+func windowKey(category string, key string) string {
+	return "window:" + category + ":" + key
+}
+
+// Adds an entry to the window and returns the number of entries within it.
+func AddToWindow(category string, key string, window time.Duration) int64 {
+	ctx := context.Background()
+	now := time.Now()
+	redisKey := windowKey(category, key)
+
+	pipe := Redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, redisKey, "-inf", strconv.FormatInt(now.Add(-window).UnixMilli(), 10))
+	pipe.ZAdd(ctx, redisKey, redis.Z{Score: float64(now.UnixMilli()), Member: strconv.FormatInt(now.UnixNano(), 10)})
+	count := pipe.ZCard(ctx, redisKey)
+	pipe.PExpire(ctx, redisKey, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		panic(err)
+	}
+
+	return count.Val()
+}
+
+// Returns the number of entries within the window and when the latest one was added.
+func CountWindow(category string, key string, window time.Duration) (int64, time.Time) {
+	ctx := context.Background()
+	now := time.Now()
+	redisKey := windowKey(category, key)
+
+	pipe := Redis.TxPipeline()
+	pipe.ZRemRangeByScore(ctx, redisKey, "-inf", strconv.FormatInt(now.Add(-window).UnixMilli(), 10))
+	count := pipe.ZCard(ctx, redisKey)
+	latest := pipe.ZRangeWithScores(ctx, redisKey, -1, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		panic(err)
+	}
+
+	var latestAt time.Time
+	if entries := latest.Val(); len(entries) > 0 {
+		latestAt = time.UnixMilli(int64(entries[0].Score))
+	}
+	return count.Val(), latestAt
+}
+
+func ClearWindow(category string, key string) {
+	if err := Redis.Del(context.Background(), windowKey(category, key)).Err(); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,73 @@
+package cron
+
+import (
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/logging"
+	"sync"
+	"time"
+)
+
+var log = logging.Logger{Package: "cron"}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func()
+}
+
+var (
+	stop    = make(chan struct{})
+	running sync.WaitGroup
+)
+
+// Runs all jobs in their interval until StopCron is called.
+func RunCron() {
+	log.Println("Starting cron system with", len(jobs), "jobs")
+
+	for _, j := range jobs {
+		running.Add(1)
+		go schedule(j)
+	}
+
+	running.Wait()
+}
+
+// Stops scheduling jobs and waits for running jobs to finish.
+func StopCron() {
+	close(stop)
+	running.Wait()
+}
+
+func schedule(j job) {
+	defer running.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			execute(j)
+		}
+	}
+}
+
+// Executes the job unless another instance already did so in the current interval.
+// The lock is not released, it expires with the interval.
+func execute(j job) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("Job %s panicked: %v", j.name, rec)
+		}
+	}()
+
+	tries := 1
+	lock := cache.NewLock("cron:"+j.name, &j.interval, &tries)
+	if err := lock.Lock(); err != nil {
+		return
+	}
+
+	j.run()
+}
@@ -0,0 +1,17 @@
+package cron
+
+import (
+	"ruehrstaat-backend/auth"
+	"ruehrstaat-backend/services/capi"
+	"ruehrstaat-backend/services/operations"
+	"ruehrstaat-backend/services/outbound"
+	"time"
+)
+
+var jobs = []job{
+	{name: "operation-reminders", interval: time.Minute, run: operations.SendReminders},
+	{name: "frontier-carrier-sync", interval: time.Minute * 15, run: capi.SyncCarriers},
+	{name: "carrier-outbound-sync", interval: time.Minute, run: outbound.ProcessQueue},
+	{name: "signing-key-rotation", interval: time.Hour, run: auth.RotateSigningKeys},
+	{name: "expired-session-cleanup", interval: time.Hour, run: auth.DeleteExpiredSessions},
+}
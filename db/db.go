@@ -28,18 +28,39 @@ func Initialize() {
 	}
 
 	err = db.AutoMigrate(
+		&entities.SigningKey{},
 		&entities.InfraToken{},
 		&entities.User{},
 		&entities.RefreshToken{},
 		&entities.Fido2Login{},
 		&entities.ApiToken{},
+		&entities.CalendarToken{},
+		&entities.FrontierLink{},
+		&entities.OAuthGrant{},
+		&entities.SecurityEvent{},
 
 		&entities.Carrier{},
+		&entities.CarrierJump{},
+		&entities.CarrierActivity{},
+		&entities.CarrierCargo{},
+		&entities.CarrierSyncJob{},
+		&entities.JumpProposal{},
+		&entities.JumpProposalVote{},
+
+		&entities.ConstructionSite{},
+		&entities.ConstructionRequirement{},
+		&entities.LogisticsRequest{},
+		&entities.Operation{},
+		&entities.OperationRsvp{},
 	)
 	if err != nil {
 		panic(err)
 	}
 
+	if err := runMigrations(db); err != nil {
+		panic(err)
+	}
+
 	log.Println("Database Migration complete")
 
 }
@@ -43,6 +43,16 @@ type Carrier struct {
 
 	// Carrier Category
 	Category CarrierCategory `gorm:"type:varchar(255);not null;default:'other'"` // other, flagship, freighter, supportvessel
+
+	// Whether the carrier shows up in public feeds and badges
+	IsPublic bool `gorm:"type:boolean;not null;default:false"`
+	// Whether the fuel level is shown on public badges
+	ShowFuelOnBadge bool `gorm:"type:boolean;not null;default:false"`
+	// Whether market data reported by the connector is forwarded to EDDN
+	PublishToEddn bool `gorm:"type:boolean;not null;default:false"`
+	// Whether jumps and stats are pushed to Inara and EDSM using the owner's API keys
+	SyncToInara bool `gorm:"type:boolean;not null;default:false"`
+	SyncToEdsm  bool `gorm:"type:boolean;not null;default:false"`
 }
 
 func (c *Carrier) AfterFind(tx *gorm.DB) (err error) {
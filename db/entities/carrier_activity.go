@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// An entry in the activity log of a carrier, used for the public feeds.
+type CarrierActivity struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CarrierID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Carrier   *Carrier  `gorm:"foreignKey:CarrierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Type    CarrierActivityType `gorm:"type:varchar(255);not null;index"` // jump, jumpCancelled, service, dockingAccess
+	Summary string              `gorm:"type:varchar(255);not null"`
+	Details string              `gorm:"type:text;not null;default:''"`
+}
+
+type CarrierActivityType string
+
+const (
+	CarrierActivityJump          CarrierActivityType = "jump"
+	CarrierActivityJumpCancelled CarrierActivityType = "jumpCancelled"
+	CarrierActivityService       CarrierActivityType = "service"
+	CarrierActivityDockingAccess CarrierActivityType = "dockingAccess"
+)
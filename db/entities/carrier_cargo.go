@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A single line of a carrier's cargo manifest.
+// Stolen and mission cargo of the same commodity are tracked as separate entries.
+type CarrierCargo struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CarrierID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_carrier_cargo_entry"`
+	Carrier   *Carrier  `gorm:"foreignKey:CarrierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// Normalized commodity name, e.g. "tritium"
+	Commodity string `gorm:"type:varchar(255);not null;uniqueIndex:idx_carrier_cargo_entry;index"`
+	Quantity  int    `gorm:"type:integer;not null;default:0"`
+
+	Stolen  bool `gorm:"type:boolean;not null;default:false;uniqueIndex:idx_carrier_cargo_entry"`
+	Mission bool `gorm:"type:boolean;not null;default:false;uniqueIndex:idx_carrier_cargo_entry"`
+}
+
+// Normalizes journal commodity names ("$tritium_name;", "Tritium") to the form stored in the manifest ("tritium").
+func NormalizeCommodity(name string) string {
+	name = strings.TrimSpace(strings.ToLower(name))
+	name = strings.TrimPrefix(name, "$")
+	name = strings.TrimSuffix(name, ";")
+	name = strings.TrimSuffix(name, "_name")
+	return name
+}
+
+// Whether both entries describe the same manifest line.
+func (cc *CarrierCargo) SameEntry(other *CarrierCargo) bool {
+	return cc.Commodity == other.Commodity && cc.Stolen == other.Stolen && cc.Mission == other.Mission
+}
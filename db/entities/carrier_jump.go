@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A jump of a carrier, either plotted ingame (pending) or scheduled ahead of time (planned).
+type CarrierJump struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CarrierID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Carrier   *Carrier  `gorm:"foreignKey:CarrierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Origin      string    `gorm:"type:varchar(255);not null;default:''"`
+	Destination string    `gorm:"type:varchar(255);not null"`
+	DepartureAt time.Time `gorm:"type:timestamp with time zone;not null;index"`
+	Note        string    `gorm:"type:text;not null;default:''"`
+
+	Status CarrierJumpStatus `gorm:"type:varchar(255);not null;default:'planned';index"` // pending, planned, completed, cancelled
+
+	// Optional user that scheduled the jump
+	CreatedByID *uuid.UUID `gorm:"type:uuid"`
+}
+
+type CarrierJumpStatus string
+
+const (
+	CarrierJumpStatusPending   CarrierJumpStatus = "pending"
+	CarrierJumpStatusPlanned   CarrierJumpStatus = "planned"
+	CarrierJumpStatusCompleted CarrierJumpStatus = "completed"
+	CarrierJumpStatusCancelled CarrierJumpStatus = "cancelled"
+)
+
+// Whether the jump is still upcoming, i.e. pending or planned.
+func (j *CarrierJump) IsScheduled() bool {
+	return j.Status == CarrierJumpStatusPending || j.Status == CarrierJumpStatusPlanned
+}
@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CarrierSyncTarget string
+
+const (
+	CarrierSyncTargetInara CarrierSyncTarget = "inara"
+	CarrierSyncTargetEdsm  CarrierSyncTarget = "edsm"
+)
+
+type CarrierSyncEvent string
+
+const (
+	// The carrier arrived in StarSystem at OccurredAt
+	CarrierSyncEventJump CarrierSyncEvent = "jump"
+	// Fuel, finances, docking and cargo space changed, the current state is pushed when processing
+	CarrierSyncEventStats CarrierSyncEvent = "stats"
+)
+
+type CarrierSyncStatus string
+
+const (
+	CarrierSyncStatusPending CarrierSyncStatus = "pending"
+	CarrierSyncStatusFailed  CarrierSyncStatus = "failed"
+)
+
+// Queued push of carrier state to a third party site. Successful jobs are deleted,
+// jobs that ran out of attempts are kept as failed.
+type CarrierSyncJob struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CarrierID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Carrier   *Carrier  `gorm:"foreignKey:CarrierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Target     CarrierSyncTarget `gorm:"type:varchar(255);not null"`
+	Event      CarrierSyncEvent  `gorm:"type:varchar(255);not null"`
+	StarSystem string            `gorm:"type:varchar(255);not null;default:''"`
+	OccurredAt time.Time         `gorm:"type:timestamp;not null"`
+
+	Status        CarrierSyncStatus `gorm:"type:varchar(255);not null;default:'pending';index"`
+	Attempts      int               `gorm:"type:integer;not null;default:0"`
+	NextAttemptAt time.Time         `gorm:"type:timestamp;not null;index"`
+	LastError     *string           `gorm:"type:text"`
+}
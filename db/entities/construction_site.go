@@ -0,0 +1,50 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A colonisation construction site supplied by the squadron's carriers.
+type ConstructionSite struct {
+	gorm.Model
+	ID uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	// Market ID of the construction depot, unknown for sites entered manually before anyone docked there
+	MarketID *string `gorm:"type:varchar(255);unique;index"`
+	System   string  `gorm:"type:varchar(255);not null;index"`
+	Name     string  `gorm:"type:varchar(255);not null"`
+
+	// Progress as reported by the depot, number between 0 and 1 inclusive
+	Progress   float64 `gorm:"type:double precision;not null;default:0"`
+	IsComplete bool    `gorm:"type:boolean;not null;default:false"`
+	IsFailed   bool    `gorm:"type:boolean;not null;default:false"`
+
+	Requirements []ConstructionRequirement `gorm:"foreignKey:SiteID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// Carriers ferrying materials to the site
+	Carriers []Carrier `gorm:"many2many:construction_site_carriers"`
+
+	// Optional user that created the site
+	CreatedByID *uuid.UUID `gorm:"type:uuid"`
+}
+
+// A commodity required by a construction site.
+type ConstructionRequirement struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	SiteID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_construction_requirement"`
+
+	// Normalized commodity name, see NormalizeCommodity
+	Commodity string `gorm:"type:varchar(255);not null;uniqueIndex:idx_construction_requirement"`
+	Label     string `gorm:"type:varchar(255);not null;default:''"`
+
+	RequiredAmount int `gorm:"type:integer;not null;default:0"`
+	ProvidedAmount int `gorm:"type:integer;not null;default:0"`
+}
+
+// Amount that still has to be delivered.
+func (r *ConstructionRequirement) Remaining() int {
+	if r.ProvidedAmount >= r.RequiredAmount {
+		return 0
+	}
+	return r.RequiredAmount - r.ProvidedAmount
+}
@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Link between a user and their Frontier account, used to sync their carrier from the Companion API.
+type FrontierLink struct {
+	gorm.Model
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	User   *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	CustomerID string `gorm:"type:varchar(255);not null;uniqueIndex"`
+	// Refresh token of the Frontier account, encrypted with util.Encrypt
+	RefreshToken string `gorm:"type:text;not null"`
+
+	LastSyncAt *time.Time `gorm:"type:timestamp"`
+	// Error of the last sync, nil if it succeeded
+	LastSyncError *string `gorm:"type:text"`
+}
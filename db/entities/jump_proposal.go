@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A destination proposed by a squadron member for a carrier, voted on by other members.
+type JumpProposal struct {
+	gorm.Model
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CarrierID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Carrier   *Carrier  `gorm:"foreignKey:CarrierID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	ProposedByID uuid.UUID `gorm:"type:uuid;not null"`
+	ProposedBy   *User     `gorm:"foreignKey:ProposedByID"`
+
+	Destination string `gorm:"type:varchar(255);not null"`
+	// Optional suggested departure, the owner may pick a different one when approving
+	DepartureAt *time.Time `gorm:"type:timestamp with time zone"`
+	Note        string     `gorm:"type:text;not null;default:''"`
+
+	// Votes are only accepted until this point in time
+	VotingEndsAt time.Time `gorm:"type:timestamp with time zone;not null"`
+
+	Status JumpProposalStatus `gorm:"type:varchar(255);not null;default:'open';index"` // open, approved, rejected, withdrawn
+
+	// Jump that was scheduled when the proposal got approved
+	JumpID *uuid.UUID `gorm:"type:uuid"`
+
+	Votes []JumpProposalVote `gorm:"foreignKey:ProposalID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+}
+
+// A vote of a member for a proposal. Members have one vote per carrier across all open proposals.
+type JumpProposalVote struct {
+	gorm.Model
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ProposalID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_jump_proposal_vote"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_jump_proposal_vote"`
+}
+
+type JumpProposalStatus string
+
+const (
+	JumpProposalStatusOpen      JumpProposalStatus = "open"
+	JumpProposalStatusApproved  JumpProposalStatus = "approved"
+	JumpProposalStatusRejected  JumpProposalStatus = "rejected"
+	JumpProposalStatusWithdrawn JumpProposalStatus = "withdrawn"
+)
+
+// Whether votes are still accepted for the proposal.
+func (p *JumpProposal) IsVotingOpen() bool {
+	return p.Status == JumpProposalStatusOpen && time.Now().Before(p.VotingEndsAt)
+}
+
+// Whether the user voted for the proposal, requires the votes to be loaded.
+func (p *JumpProposal) HasVoted(userID uuid.UUID) bool {
+	for _, vote := range p.Votes {
+		if vote.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A haul or jump requested by a squadron member from the carrier managers.
+type LogisticsRequest struct {
+	gorm.Model
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RequesterID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Requester   *User     `gorm:"foreignKey:RequesterID"`
+
+	Type LogisticsRequestType `gorm:"type:varchar(255);not null;default:'haul'"` // haul, jump
+
+	// Normalized commodity name, see NormalizeCommodity. Empty for jump requests
+	Commodity         string     `gorm:"type:varchar(255);not null;default:'';index"`
+	Quantity          int        `gorm:"type:integer;not null;default:0"`
+	DestinationSystem string     `gorm:"type:varchar(255);not null"`
+	Deadline          *time.Time `gorm:"type:timestamp with time zone"`
+	Note              string     `gorm:"type:text;not null;default:''"`
+
+	Status LogisticsRequestStatus `gorm:"type:varchar(255);not null;default:'open';index"` // open, claimed, accepted, declined, completed, cancelled
+
+	// Carrier handling the request, optionally chosen by the requester up front
+	CarrierID *uuid.UUID `gorm:"type:uuid;index"`
+	Carrier   *Carrier   `gorm:"foreignKey:CarrierID"`
+
+	// Carrier manager that last changed the status
+	HandledByID *uuid.UUID `gorm:"type:uuid"`
+	// Optional message of the carrier manager, e.g. the reason for declining
+	Response string `gorm:"type:text;not null;default:''"`
+}
+
+type LogisticsRequestType string
+
+const (
+	LogisticsRequestTypeHaul LogisticsRequestType = "haul"
+	LogisticsRequestTypeJump LogisticsRequestType = "jump"
+)
+
+type LogisticsRequestStatus string
+
+const (
+	LogisticsRequestStatusOpen      LogisticsRequestStatus = "open"
+	LogisticsRequestStatusClaimed   LogisticsRequestStatus = "claimed"
+	LogisticsRequestStatusAccepted  LogisticsRequestStatus = "accepted"
+	LogisticsRequestStatusDeclined  LogisticsRequestStatus = "declined"
+	LogisticsRequestStatusCompleted LogisticsRequestStatus = "completed"
+	LogisticsRequestStatusCancelled LogisticsRequestStatus = "cancelled"
+)
+
+// Whether the request is still waiting to be fulfilled.
+func (r *LogisticsRequest) IsActive() bool {
+	return r.Status == LogisticsRequestStatusOpen || r.Status == LogisticsRequestStatusClaimed || r.Status == LogisticsRequestStatusAccepted
+}
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Authorization a user gave an OAuth2 client. The client holds a rotating refresh token for it,
+// access tokens are issued as api tokens linked to the grant.
+type OAuthGrant struct {
+	ID       uuid.UUID   `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ClientID uuid.UUID   `gorm:"type:uuid;not null;index"`
+	Client   *InfraToken `gorm:"foreignKey:ClientID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	UserID   uuid.UUID   `gorm:"type:uuid;not null;index"`
+	User     *User       `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Scopes pq.StringArray `gorm:"type:varchar(255)[];not null;default:'{}'"`
+
+	RefreshTokenHash string    `gorm:"type:varchar(64);not null;unique;index"` // hex encoded sha256 of the refresh token
+	RefreshExpiresAt time.Time `gorm:"type:timestamp with time zone;not null"`
+	IsRevoked        bool      `gorm:"type:boolean;not null;default:false"`
+
+	CreatedAt       time.Time  `gorm:"type:timestamp with time zone;not null;default:now()"`
+	LastRefreshedAt *time.Time `gorm:"type:timestamp with time zone"`
+}
+
+func (g *OAuthGrant) IsActive() bool {
+	return !g.IsRevoked && g.RefreshExpiresAt.After(time.Now())
+}
+
+func (OAuthGrant) TableName() string {
+	return "oauth_grants"
+}
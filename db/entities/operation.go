@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// A squadron operation like a mining op, an expedition or a community goal.
+type Operation struct {
+	gorm.Model
+	ID          uuid.UUID         `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Title       string            `gorm:"type:varchar(255);not null"`
+	Description string            `gorm:"type:text;not null;default:''"`
+	Category    OperationCategory `gorm:"type:varchar(255);not null;default:'other'"` // mining, expedition, communitygoal, other
+
+	StartsAt time.Time `gorm:"type:timestamp with time zone;not null;index"`
+	EndsAt   time.Time `gorm:"type:timestamp with time zone;not null"`
+
+	TargetSystems pq.StringArray  `gorm:"type:varchar(255)[];not null;default:'{}'"`
+	Carriers      []Carrier       `gorm:"many2many:operation_carriers"`
+	Rsvps         []OperationRsvp `gorm:"foreignKey:OperationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	// Whether the operation shows up in the public api
+	IsPublic bool `gorm:"type:boolean;not null;default:false"`
+
+	// Set once the reminder emails went out, reset when the start changes
+	ReminderSentAt *time.Time `gorm:"type:timestamp with time zone"`
+
+	CreatedByID *uuid.UUID `gorm:"type:uuid"`
+}
+
+// Response of a user to an operation.
+type OperationRsvp struct {
+	gorm.Model
+	ID          uuid.UUID           `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OperationID uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex:idx_operation_rsvp"`
+	UserID      uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex:idx_operation_rsvp"`
+	User        *User               `gorm:"foreignKey:UserID"`
+	Status      OperationRsvpStatus `gorm:"type:varchar(255);not null;default:'going'"` // going, maybe, declined
+	Note        string              `gorm:"type:varchar(255);not null;default:''"`
+}
+
+type OperationCategory string
+
+const (
+	OperationCategoryMining        OperationCategory = "mining"
+	OperationCategoryExpedition    OperationCategory = "expedition"
+	OperationCategoryCommunityGoal OperationCategory = "communitygoal"
+	OperationCategoryOther         OperationCategory = "other"
+)
+
+type OperationRsvpStatus string
+
+const (
+	OperationRsvpGoing    OperationRsvpStatus = "going"
+	OperationRsvpMaybe    OperationRsvpStatus = "maybe"
+	OperationRsvpDeclined OperationRsvpStatus = "declined"
+)
+
+// Counts the RSVPs with the given status, requires the RSVPs to be loaded.
+func (o *Operation) CountRsvps(status OperationRsvpStatus) int {
+	count := 0
+	for _, rsvp := range o.Rsvps {
+		if rsvp.Status == status {
+			count++
+		}
+	}
+	return count
+}
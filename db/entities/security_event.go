@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Security relevant event on a user account, shown to the user and admins.
+type SecurityEvent struct {
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;index"`
+	User   *User     `gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+
+	Type      SecurityEventType `gorm:"type:varchar(64);not null;index"`
+	IP        string            `gorm:"type:varchar(64);not null;default:''"`
+	UserAgent string            `gorm:"type:text;not null;default:''"`
+	Details   string            `gorm:"type:text;not null;default:''"`
+	CreatedAt time.Time         `gorm:"type:timestamp with time zone;not null;default:now();index"`
+}
+
+type SecurityEventType string
+
+const (
+	// an already rotated refresh token was presented again, its session was ended
+	SecurityEventRefreshTokenReuse SecurityEventType = "refreshTokenReuse"
+	// too many failed login attempts, the account was locked temporarily
+	SecurityEventAccountLocked SecurityEventType = "accountLocked"
+)
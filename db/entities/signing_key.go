@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Key pair all JWTs are signed with. The newest key that is not retired signs new tokens,
+// retired keys keep verifying tokens until VerifyUntil so tokens issued before a rotation stay valid.
+type SigningKey struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Kid        string    `gorm:"type:varchar(64);not null;unique;index"`
+	Algorithm  string    `gorm:"type:varchar(16);not null"`
+	PrivateKey string    `gorm:"type:text;not null"` // PKCS8 DER encrypted with util.Encrypt
+	PublicKey  string    `gorm:"type:text;not null"` // base64 encoded PKIX DER
+
+	CreatedAt   time.Time  `gorm:"type:timestamp with time zone;not null;default:now()"`
+	RetiredAt   *time.Time `gorm:"type:timestamp with time zone"`
+	VerifyUntil *time.Time `gorm:"type:timestamp with time zone;index"`
+}
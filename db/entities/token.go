@@ -1,56 +1,218 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-// Third party applications that can access the API.
+type InfraScope string
+
+const (
+	InfraScopeUsersRead         InfraScope = "users:read"
+	InfraScopeCarriersRead      InfraScope = "carriers:read"
+	InfraScopeNotificationsSend InfraScope = "notifications:send"
+)
+
+var InfraScopes = []InfraScope{InfraScopeUsersRead, InfraScopeCarriersRead, InfraScopeNotificationsSend}
+
+// Third party applications that can access the API, e.g. the Discord bot. They authenticate with their id
+// and secret and act on their own behalf instead of impersonating a user.
 type InfraToken struct {
-	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name   string    `gorm:"type:varchar(255);not null;unique;index"`
-	Secret string    `gorm:"type:varchar(255);not null"`
+	ID         uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name       string         `gorm:"type:varchar(255);not null;unique;index"`
+	SecretHash string         `gorm:"column:secret;type:varchar(255);not null"` // bcrypt hash of the secret
+	Scopes     pq.StringArray `gorm:"type:varchar(255)[];not null;default:'{}'"`
+	IsRevoked  bool           `gorm:"type:boolean;not null;default:false"`
+
+	// After a rotation the previous secret keeps working until PreviousSecretExpiresAt,
+	// so deployments using the client can be updated without downtime.
+	PreviousSecretHash      *string    `gorm:"type:varchar(255)"`
+	PreviousSecretExpiresAt *time.Time `gorm:"type:timestamp with time zone"`
+
+	// OAuth2, clients without redirect uris can not be used for "Log in with Ruehrstaat".
+	// Redirect uris are matched exactly, OAuthScopes are the user scopes the client may request.
+	// Public clients (e.g. single page apps) can not keep a secret and only authenticate with PKCE.
+	RedirectURIs   pq.StringArray `gorm:"column:redirect_uris;type:text[];not null;default:'{}'"`
+	OAuthScopes    pq.StringArray `gorm:"column:oauth_scopes;type:varchar(255)[];not null;default:'{}'"`
+	IsPublicClient bool           `gorm:"type:boolean;not null;default:false"`
+
+	CreatedAt       time.Time  `gorm:"type:timestamp with time zone;not null;default:now()"`
+	SecretRotatedAt *time.Time `gorm:"type:timestamp with time zone"`
+	LastUsedAt      *time.Time `gorm:"type:timestamp with time zone"`
+}
+
+// Whether the redirect uri is registered for the client, only exact matches count.
+func (t *InfraToken) HasRedirectURI(uri string) bool {
+	for _, registered := range t.RedirectURIs {
+		if registered == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *InfraToken) AllowsOAuthScope(scope string) bool {
+	for _, allowed := range t.OAuthScopes {
+		if allowed == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *InfraToken) HasScope(scope InfraScope) bool {
+	for _, s := range t.Scopes {
+		if InfraScope(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type ApiTokenScope string
+
+const (
+	ScopeCarrierRead       ApiTokenScope = "carrier:read"
+	ScopeCarrierWrite      ApiTokenScope = "carrier:write"
+	ScopeCarrierConnector  ApiTokenScope = "carrier:connector"
+	ScopeConstructionRead  ApiTokenScope = "construction:read"
+	ScopeConstructionWrite ApiTokenScope = "construction:write"
+	ScopeUserRead          ApiTokenScope = "user:read"
+)
+
+// OpenID Connect scopes, only oauth clients can request them.
+const (
+	ScopeOpenID  ApiTokenScope = "openid"
+	ScopeProfile ApiTokenScope = "profile"
+	ScopeEmail   ApiTokenScope = "email"
+)
+
+var OIDCScopes = []ApiTokenScope{ScopeOpenID, ScopeProfile, ScopeEmail}
+
+var ApiTokenScopes = []ApiTokenScope{ScopeCarrierRead, ScopeCarrierWrite, ScopeCarrierConnector, ScopeConstructionRead, ScopeConstructionWrite, ScopeUserRead}
+
+// Whether the scope can be restricted to single carriers.
+func (s ApiTokenScope) IsCarrierScope() bool {
+	return s == ScopeCarrierRead || s == ScopeCarrierWrite || s == ScopeCarrierConnector
+}
+
+// Resource of a scope entry granting access to all carriers, not only those the user may access.
+const AllCarriers = "*"
+
+// Splits a scope entry like "carrier:write:<carrier id>" into scope and resource.
+// Entries without resource apply to everything the user may access.
+func ParseScopeEntry(entry string) (ApiTokenScope, string) {
+	parts := strings.SplitN(entry, ":", 3)
+	if len(parts) < 3 {
+		return ApiTokenScope(entry), ""
+	}
+	return ApiTokenScope(parts[0] + ":" + parts[1]), parts[2]
 }
 
 // API tokens for users.
 type ApiToken struct {
 	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	Name      string    `gorm:"type:varchar(255);not null;default:''"`
 	IsRevoked bool      `gorm:"type:boolean;not null;default:false"`
 	ExpiresAt time.Time `gorm:"type:timestamp with time zone;not null;default:now() + interval '1 year'"`
 	Prefix    string    `gorm:"type:varchar(255);not null;default:'';index"`
 	Token     string    `gorm:"type:varchar(255);not null;default:''"`
 
-	// Access rights
-	HasFullReadAccess  bool `gorm:"type:boolean;not null;default:false"`
-	HasFullWriteAccess bool `gorm:"type:boolean;not null;default:false"`
+	CreatedAt  time.Time  `gorm:"type:timestamp with time zone;not null;default:now()"`
+	LastUsedAt *time.Time `gorm:"type:timestamp with time zone"`
+
+	// OAuth2 grant the token was issued for as access token, nil for tokens created by the user
+	GrantID *uuid.UUID `gorm:"type:uuid;index"`
 
-	// Carrier Access
-	HasReadAccessTo  []uuid.UUID `gorm:"type:uuid[];not null;default:'{}'"`
-	HasWriteAccessTo []uuid.UUID `gorm:"type:uuid[];not null;default:'{}'"`
+	// Scope entries, either a plain scope ("carrier:read") limited to what the user may access,
+	// or a scope restricted to a carrier ("carrier:read:<carrier id>") or granted for all carriers ("carrier:read:*").
+	// Carrier resources beyond the user's own carriers can only be granted by admins.
+	Scopes pq.StringArray `gorm:"type:varchar(255)[];not null;default:'{}'"`
 }
 
-func (t *ApiToken) HasReadAccessToCarrier(carrierId uuid.UUID) bool {
-	if t.HasFullReadAccess {
-		return true
+// Whether any entry of the token carries the scope.
+func (t *ApiToken) HasScope(scope ApiTokenScope) bool {
+	for _, entry := range t.Scopes {
+		if s, _ := ParseScopeEntry(entry); s == scope {
+			return true
+		}
 	}
-	for _, id := range t.HasReadAccessTo {
-		if id == carrierId {
+	return false
+}
+
+// Whether the token carries the scope without resource, i.e. for everything the user may access.
+func (t *ApiToken) HasUnrestrictedScope(scope ApiTokenScope) bool {
+	for _, entry := range t.Scopes {
+		if s, resource := ParseScopeEntry(entry); s == scope && resource == "" {
 			return true
 		}
 	}
 	return false
 }
 
-func (t *ApiToken) HasWriteAccessToCarrier(carrierId uuid.UUID) bool {
-	if t.HasFullWriteAccess {
-		return true
+// Whether the token may be used for the scope on the carrier at all. The user still needs access to the carrier
+// unless the token grants it explicitly.
+func (t *ApiToken) CoversCarrier(scope ApiTokenScope, carrierId uuid.UUID) bool {
+	for _, entry := range t.Scopes {
+		s, resource := ParseScopeEntry(entry)
+		if s == scope && (resource == "" || resource == AllCarriers || resource == carrierId.String()) {
+			return true
+		}
 	}
-	for _, id := range t.HasWriteAccessTo {
-		if id == carrierId {
+	return false
+}
+
+// Whether the token grants the scope on the carrier explicitly, independent of the user's own access.
+func (t *ApiToken) GrantsCarrier(scope ApiTokenScope, carrierId uuid.UUID) bool {
+	for _, entry := range t.Scopes {
+		s, resource := ParseScopeEntry(entry)
+		if s == scope && (resource == AllCarriers || resource == carrierId.String()) {
 			return true
 		}
 	}
 	return false
 }
+
+// Whether the token grants the scope on every carrier.
+func (t *ApiToken) GrantsAllCarriers(scope ApiTokenScope) bool {
+	for _, entry := range t.Scopes {
+		if s, resource := ParseScopeEntry(entry); s == scope && resource == AllCarriers {
+			return true
+		}
+	}
+	return false
+}
+
+// Carriers the token grants the scope on explicitly.
+func (t *ApiToken) GrantedCarriers(scope ApiTokenScope) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, entry := range t.Scopes {
+		s, resource := ParseScopeEntry(entry)
+		if s != scope {
+			continue
+		}
+		if id, err := uuid.Parse(resource); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Whether the token is neither revoked nor expired.
+func (t *ApiToken) IsActive() bool {
+	return !t.IsRevoked && t.ExpiresAt.After(time.Now())
+}
+
+// Secret token used in calendar feed URLs. Independent of login sessions so it can be
+// revoked without logging the user out (and vice versa).
+type CalendarToken struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);not null;unique;index"` // hex encoded sha256 of the token
+	IsRevoked bool      `gorm:"type:boolean;not null;default:false"`
+	CreatedAt time.Time
+}
@@ -1,6 +1,8 @@
 package entities
 
 import (
+	"time"
+
 	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	jsoniter "github.com/json-iterator/go"
@@ -20,9 +22,19 @@ type User struct {
 
 	Locale string `gorm:"type:varchar(2);default:en"`
 
+	// Whether the user opted out of presence tracking aboard carriers
+	HidePresence bool `gorm:"type:boolean;not null;default:false"`
+
+	// API keys for pushing carrier state to Inara and EDSM, encrypted with util.Encrypt
+	InaraApiKey *string `gorm:"type:text"`
+	EdsmApiKey  *string `gorm:"type:text"`
+
 	// user banned
 	IsBanned bool `gorm:"type:boolean;default:false"`
 
+	// identity and refresh tokens issued up to this point are rejected, set when all sessions are ended
+	TokensValidAfter *time.Time `gorm:"type:timestamp with time zone"`
+
 	// activation
 	IsActivated        bool    `gorm:"type:boolean;default:false"`
 	ActivationToken    *string `gorm:"type:text"`
@@ -48,11 +60,19 @@ func (u *User) HasTwoFactor() bool {
 	return u.OtpActive && u.OtpVerified
 }
 
+// Session of a user on one device. The refresh token is rotated in place on every refresh, so all refresh tokens
+// of a session form one family. The ID is the "sid" claim of all tokens issued for the session.
 type RefreshToken struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;index"`
-	Token     string    `gorm:"type:text;not null;index"`
-	IsRevoked bool      `gorm:"type:boolean;default:false"`
+	ID     uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;index"`
+	Token  string    `gorm:"type:text;not null;index"`
+
+	LoginMethod string    `gorm:"type:varchar(32);not null;default:''"`
+	IP          string    `gorm:"type:varchar(64);not null;default:''"` // of the last use
+	UserAgent   string    `gorm:"type:text;not null;default:''"`        // of the last use
+	CreatedAt   time.Time `gorm:"type:timestamp with time zone;not null;default:now()"`
+	LastUsedAt  time.Time `gorm:"type:timestamp with time zone;not null;default:now()"`
+	ExpiresAt   time.Time `gorm:"type:timestamp with time zone;not null;default:'epoch'"` // absolute, refreshing doesn't extend it
 }
 
 type Fido2Login struct {
@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"database/sql/driver"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// List of uuids stored as a postgres uuid[] column.
+type UUIDArray []uuid.UUID
+
+func (a *UUIDArray) Scan(src interface{}) error {
+	values := pq.StringArray{}
+	if err := values.Scan(src); err != nil {
+		return err
+	}
+
+	ids := UUIDArray{}
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	*a = ids
+	return nil
+}
+
+func (a UUIDArray) Value() (driver.Value, error) {
+	values := pq.StringArray{}
+	for _, id := range a {
+		values = append(values, id.String())
+	}
+	return values.Value()
+}
+
+func (a UUIDArray) Contains(id uuid.UUID) bool {
+	for _, other := range a {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// Data migrations AutoMigrate cannot express, each one has to be safe to run on every startup.
+func runMigrations(db *gorm.DB) error {
+	if err := migrateApiTokenScopes(db); err != nil {
+		return err
+	}
+	if err := hashInfraSecrets(db); err != nil {
+		return err
+	}
+	if err := migrateSessions(db); err != nil {
+		return err
+	}
+	return backfillSessionExpiry(db)
+}
+
+// Converts the former access flags of api tokens to scope entries and drops the old columns.
+// Tokens used to act with all rights of their user, so every token keeps the plain scopes,
+// full access becomes a grant on all carriers and per carrier access a grant on that carrier.
+func migrateApiTokenScopes(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entities.ApiToken{}, "has_full_read_access") {
+		return nil
+	}
+
+	log.Println("Migrating api token access flags to scopes")
+
+	type legacyToken struct {
+		ID                 uuid.UUID
+		HasFullReadAccess  bool
+		HasFullWriteAccess bool
+		HasReadAccessTo    entities.UUIDArray
+		HasWriteAccessTo   entities.UUIDArray
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		tokens := []legacyToken{}
+		if res := tx.Table("api_tokens").Select("id, has_full_read_access, has_full_write_access, has_read_access_to, has_write_access_to").Scan(&tokens); res.Error != nil {
+			return res.Error
+		}
+
+		for _, token := range tokens {
+			scopes := []string{
+				string(entities.ScopeCarrierRead),
+				string(entities.ScopeCarrierWrite),
+				string(entities.ScopeCarrierConnector),
+				string(entities.ScopeConstructionRead),
+				string(entities.ScopeConstructionWrite),
+			}
+
+			// writing carriers was always possible through the connector as well
+			if token.HasFullReadAccess {
+				scopes = append(scopes, string(entities.ScopeCarrierRead)+":"+entities.AllCarriers)
+			}
+			if token.HasFullWriteAccess {
+				scopes = append(scopes, string(entities.ScopeCarrierWrite)+":"+entities.AllCarriers, string(entities.ScopeCarrierConnector)+":"+entities.AllCarriers)
+			}
+			for _, id := range token.HasReadAccessTo {
+				scopes = append(scopes, string(entities.ScopeCarrierRead)+":"+id.String())
+			}
+			for _, id := range token.HasWriteAccessTo {
+				scopes = append(scopes, string(entities.ScopeCarrierWrite)+":"+id.String(), string(entities.ScopeCarrierConnector)+":"+id.String())
+			}
+
+			if res := tx.Model(&entities.ApiToken{}).Where("id = ?", token.ID).Update("scopes", pq.StringArray(scopes)); res.Error != nil {
+				return res.Error
+			}
+		}
+
+		for _, column := range []string{"has_full_read_access", "has_full_write_access", "has_read_access_to", "has_write_access_to"} {
+			if err := tx.Migrator().DropColumn(&entities.ApiToken{}, column); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Infra client secrets used to be stored in plaintext, hashes them in place. Bcrypt hashes
+// are recognized by their prefix, so already hashed secrets are left alone.
+func hashInfraSecrets(db *gorm.DB) error {
+	clients := []entities.InfraToken{}
+	if res := db.Select("id, secret").Find(&clients); res.Error != nil {
+		return res.Error
+	}
+
+	for _, client := range clients {
+		if strings.HasPrefix(client.SecretHash, "$2") {
+			continue
+		}
+
+		log.Println("Hashing secret of infra client", client.ID)
+
+		hashed, err := bcrypt.GenerateFromPassword([]byte(client.SecretHash), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		if res := db.Model(&entities.InfraToken{}).Where("id = ?", client.ID).Update("secret", string(hashed)); res.Error != nil {
+			return res.Error
+		}
+	}
+
+	return nil
+}
+
+// Refresh tokens became sessions that are rotated in place instead of keeping a revoked row per used token.
+// The former rows were signed with the shared secrets and can't be used anymore, so they are dropped.
+func migrateSessions(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&entities.RefreshToken{}, "is_revoked") {
+		return nil
+	}
+
+	log.Println("Dropping legacy refresh tokens")
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Exec("DELETE FROM refresh_tokens"); res.Error != nil {
+			return res.Error
+		}
+		return tx.Migrator().DropColumn(&entities.RefreshToken{}, "is_revoked")
+	})
+}
+
+// Sessions created before they had an absolute lifetime get the default lifetime from their creation on.
+func backfillSessionExpiry(db *gorm.DB) error {
+	return db.Exec("UPDATE refresh_tokens SET expires_at = created_at + interval '30 days' WHERE expires_at <= created_at").Error
+}
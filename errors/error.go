@@ -88,6 +88,27 @@ func ReturnWithError(c *gin.Context, err *RstError) {
 	})
 }
 
+// Writes the error the way OAuth endpoints have to (RFC 6749 section 5.2, RFC 8628 section 3.5), with the
+// registered error code in "error". Errors without a registered code become invalid_request or server_error.
+func ReturnWithOAuthError(c *gin.Context, err *RstError) {
+	c.Error(err)
+
+	name := err.nickname
+	if name == "" {
+		name = "invalid_request"
+		if err.HtmlCode() >= 500 {
+			name = "server_error"
+		}
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(err.HtmlCode(), gin.H{
+		"error":             name,
+		"error_description": err.message,
+		"code":              err.Code(),
+	})
+}
+
 func MiddlewareAbortWithError(c *gin.Context, err *RstError) {
 	c.Error(err)
 	c.JSON(err.HtmlCode(), gin.H{
@@ -0,0 +1,40 @@
+package mails
+
+import (
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sent when an account was locked after too many failed login attempts, the link lifts the lockout.
+type AccountLockedMail struct {
+	UserID   uuid.UUID
+	Nickname string
+	Token    string
+	Until    time.Time
+}
+
+func (m AccountLockedMail) GetSubject(locale string) string {
+	switch locale {
+	case "de":
+		return "Dein Ruehrstaat-Konto wurde vorübergehend gesperrt"
+	default:
+		return "Your Ruehrstaat account was locked temporarily"
+	}
+}
+
+func (m AccountLockedMail) GetBody(locale string) string {
+	link := os.Getenv("FRONTEND_URL") + "/unlock/" + m.UserID.String() + "?unlock=" + m.Token
+	until := m.Until.UTC().Format("2006-01-02 15:04") + " UTC"
+	switch locale {
+	case "de":
+		return "Nach zu vielen fehlgeschlagenen Anmeldeversuchen wurde dein Konto bis " + until + " gesperrt. Falls du das warst, kannst du es hier sofort entsperren: \n<a href=\"" + link + "\">Jetzt entsperren!</a>\n\nFalls dieser nicht geht, versuche diesen Link:\n<a href=\"" + link + "\">" + link + "</a>\n\nFalls du das nicht warst, versucht jemand, sich in dein Konto einzuloggen. Ändere in diesem Fall am besten dein Passwort und aktiviere die Zwei-Faktor-Authentifizierung."
+	default:
+		return "After too many failed login attempts your account was locked until " + until + ". If this was you, you can unlock it right away: \n<a href=\"" + link + "\">Unlock now!</a>\n\nIf this does not work, try this link:\n<a href=\"" + link + "\">" + link + "</a>\n\nIf this wasn't you, someone is trying to log into your account. In that case, better change your password and enable two factor authentication."
+	}
+}
+
+func (m AccountLockedMail) GetName() string {
+	return m.Nickname
+}
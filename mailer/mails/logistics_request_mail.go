@@ -0,0 +1,76 @@
+package mails
+
+import (
+	"html"
+	"os"
+
+	"github.com/google/uuid"
+)
+
+type LogisticsRequestStatusMail struct {
+	RequestID   uuid.UUID
+	Nickname    string
+	Summary     string
+	Status      string
+	CarrierName string
+	Response    string
+}
+
+func (m LogisticsRequestStatusMail) GetSubject(locale string) string {
+	switch locale {
+	case "de":
+		return "Deine Logistik-Anfrage wurde aktualisiert"
+	default:
+		return "Your logistics request has been updated"
+	}
+}
+
+func (m LogisticsRequestStatusMail) GetBody(locale string) string {
+	link := os.Getenv("FRONTEND_URL") + "/logistics/" + m.RequestID.String()
+	summary := html.EscapeString(m.Summary)
+	carrierName := html.EscapeString(m.CarrierName)
+	response := html.EscapeString(m.Response)
+	switch locale {
+	case "de":
+		body := "Deine Anfrage \"" + summary + "\" hat den neuen Status: <b>" + statusLabelDe(m.Status) + "</b>\n"
+		if m.CarrierName != "" {
+			body += "Carrier: " + carrierName + "\n"
+		}
+		if m.Response != "" {
+			body += "Nachricht: " + response + "\n"
+		}
+		return body + "\n<a href=\"" + link + "\">Anfrage ansehen</a>"
+	default:
+		body := "Your request \"" + summary + "\" has a new status: <b>" + m.Status + "</b>\n"
+		if m.CarrierName != "" {
+			body += "Carrier: " + carrierName + "\n"
+		}
+		if m.Response != "" {
+			body += "Message: " + response + "\n"
+		}
+		return body + "\n<a href=\"" + link + "\">View request</a>"
+	}
+}
+
+func (m LogisticsRequestStatusMail) GetName() string {
+	return m.Nickname
+}
+
+func statusLabelDe(status string) string {
+	switch status {
+	case "open":
+		return "offen"
+	case "claimed":
+		return "übernommen"
+	case "accepted":
+		return "angenommen"
+	case "declined":
+		return "abgelehnt"
+	case "completed":
+		return "erledigt"
+	case "cancelled":
+		return "storniert"
+	default:
+		return status
+	}
+}
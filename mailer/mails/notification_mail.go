@@ -0,0 +1,35 @@
+package mails
+
+import "html"
+
+// Notification sent on behalf of an infra client, e.g. the Discord bot. Subject and message are
+// given by the client and not localized.
+type NotificationMail struct {
+	Nickname string
+	Subject  string
+	Message  string
+	Link     string
+}
+
+func (m NotificationMail) GetSubject(locale string) string {
+	return m.Subject
+}
+
+func (m NotificationMail) GetBody(locale string) string {
+	body := html.EscapeString(m.Message)
+	if m.Link == "" {
+		return body
+	}
+
+	link := html.EscapeString(m.Link)
+	switch locale {
+	case "de":
+		return body + "\n\n<a href=\"" + link + "\">Öffnen</a>"
+	default:
+		return body + "\n\n<a href=\"" + link + "\">Open</a>"
+	}
+}
+
+func (m NotificationMail) GetName() string {
+	return m.Nickname
+}
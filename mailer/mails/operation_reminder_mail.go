@@ -0,0 +1,52 @@
+package mails
+
+import (
+	"html"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OperationReminderMail struct {
+	OperationID   uuid.UUID
+	Nickname      string
+	Title         string
+	StartsAt      time.Time
+	TargetSystems []string
+}
+
+func (m OperationReminderMail) GetSubject(locale string) string {
+	switch locale {
+	case "de":
+		return "Erinnerung: " + m.Title
+	default:
+		return "Reminder: " + m.Title
+	}
+}
+
+func (m OperationReminderMail) GetBody(locale string) string {
+	link := os.Getenv("FRONTEND_URL") + "/operations/" + m.OperationID.String()
+	title := html.EscapeString(m.Title)
+	systems := html.EscapeString(strings.Join(m.TargetSystems, ", "))
+	startsAt := m.StartsAt.UTC().Format("2006-01-02 15:04") + " UTC"
+	switch locale {
+	case "de":
+		body := "Die Operation <b>" + title + "</b>, für die du dich angemeldet hast, beginnt am " + startsAt + ".\n"
+		if systems != "" {
+			body += "Zielsysteme: " + systems + "\n"
+		}
+		return body + "\n<a href=\"" + link + "\">Operation ansehen</a>"
+	default:
+		body := "The operation <b>" + title + "</b> you signed up for starts at " + startsAt + ".\n"
+		if systems != "" {
+			body += "Target systems: " + systems + "\n"
+		}
+		return body + "\n<a href=\"" + link + "\">View operation</a>"
+	}
+}
+
+func (m OperationReminderMail) GetName() string {
+	return m.Nickname
+}
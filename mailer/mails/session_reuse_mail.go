@@ -0,0 +1,45 @@
+package mails
+
+import (
+	"html"
+	"os"
+	"time"
+)
+
+// Security alert sent when an already used refresh token of a session shows up again.
+type SessionReuseMail struct {
+	Nickname  string
+	IP        string
+	UserAgent string
+	At        time.Time
+}
+
+func (m SessionReuseMail) GetSubject(locale string) string {
+	switch locale {
+	case "de":
+		return "Sicherheitswarnung für dein Ruehrstaat-Konto"
+	default:
+		return "Security alert for your Ruehrstaat account"
+	}
+}
+
+func (m SessionReuseMail) GetBody(locale string) string {
+	link := os.Getenv("FRONTEND_URL")
+	at := m.At.UTC().Format("2006-01-02 15:04") + " UTC"
+	ip := html.EscapeString(m.IP)
+	userAgent := html.EscapeString(m.UserAgent)
+	switch locale {
+	case "de":
+		return "Am " + at + " wurde ein bereits verwendetes Anmeldetoken einer deiner Sitzungen erneut benutzt (IP: " + ip + ", Gerät: " + userAgent + "). " +
+			"Das kann bedeuten, dass jemand dieses Token kopiert hat, deshalb wurde die Sitzung beendet.\n\n" +
+			"Falls du dir nicht sicher bist, ändere dein Passwort und beende alle Sitzungen.\n<a href=\"" + link + "\">Zu Ruehrstaat</a>"
+	default:
+		return "At " + at + " an already used login token of one of your sessions was used again (IP: " + ip + ", device: " + userAgent + "). " +
+			"This can mean that someone copied the token, so the session was ended.\n\n" +
+			"If you are unsure, change your password and end all sessions.\n<a href=\"" + link + "\">Go to Ruehrstaat</a>"
+	}
+}
+
+func (m SessionReuseMail) GetName() string {
+	return m.Nickname
+}
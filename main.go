@@ -9,10 +9,13 @@ import (
 	"ruehrstaat-backend/api"
 	"ruehrstaat-backend/auth"
 	"ruehrstaat-backend/auth/discord"
+	"ruehrstaat-backend/auth/frontier"
 	"ruehrstaat-backend/cache"
 	"ruehrstaat-backend/constants"
+	"ruehrstaat-backend/cron"
 	"ruehrstaat-backend/db"
 	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/services/eddn"
 	"runtime"
 
 	"github.com/getsentry/sentry-go"
@@ -42,7 +45,12 @@ func main() {
 
 	if os.Getenv("CRON") == "true" {
 		log.Println("Shutting down cron system...")
-		//cron.StopCron()
+		cron.StopCron()
+	}
+
+	if os.Getenv("EDDN") == "true" {
+		log.Println("Shutting down EDDN listener...")
+		eddn.Stop()
 	}
 
 	log.Println("Shutdown complete")
@@ -62,9 +70,11 @@ func setup() {
 	}
 
 	discord.Initialize()
+	frontier.Initialize()
 	auth.InitializeWebauthn()
 
 	db.Initialize()
+	auth.InitializeSigningKeys()
 	cache.Initialize()
 
 	r := gin.New()
@@ -77,12 +87,18 @@ func setup() {
 	r.Use(errorLogger())
 
 	api.RegisterRoutes(&r.RouterGroup)
-	/*
-		if os.Getenv("CRON") == "true" {
-			go cron.RunCron()
-		} else {
-			log.Println("Cron system disabled")
-		}*/
+
+	if os.Getenv("CRON") == "true" {
+		go cron.RunCron()
+	} else {
+		log.Println("Cron system disabled")
+	}
+
+	if os.Getenv("EDDN") == "true" {
+		eddn.Start()
+	} else {
+		log.Println("EDDN listener disabled")
+	}
 
 	err := r.Run(":8000")
 	if err != nil {
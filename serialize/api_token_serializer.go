@@ -0,0 +1,29 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type ApiTokenSerializer struct {
+	// Whether to include the user the token belongs to, used for admin listings
+	WithUser bool
+}
+
+func (s *ApiTokenSerializer) Serialize(token entities.ApiToken) interface{} {
+	obj := &JsonObj{
+		"id":         token.ID,
+		"name":       token.Name,
+		"prefix":     token.Prefix,
+		"createdAt":  token.CreatedAt,
+		"expiresAt":  token.ExpiresAt,
+		"lastUsedAt": token.LastUsedAt,
+		"isRevoked":  token.IsRevoked,
+		"isActive":   token.IsActive(),
+		"scopes":     token.Scopes,
+	}
+
+	if s.WithUser {
+		obj.Add("userId", token.UserID)
+	}
+	return obj
+}
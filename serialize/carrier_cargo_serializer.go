@@ -0,0 +1,23 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type CarrierCargoSerializer struct {
+}
+
+func (s *CarrierCargoSerializer) Serialize(cargo entities.CarrierCargo) interface{} {
+	obj := &JsonObj{
+		"commodity": cargo.Commodity,
+		"quantity":  cargo.Quantity,
+		"stolen":    cargo.Stolen,
+		"mission":   cargo.Mission,
+		"updatedAt": cargo.UpdatedAt,
+	}
+
+	if cargo.Carrier != nil {
+		obj.Add("carrier", Do[entities.Carrier](&CarrierSerializer{Limited: true}, *cargo.Carrier))
+	}
+	return obj
+}
@@ -0,0 +1,22 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type CarrierJumpSerializer struct {
+}
+
+func (s *CarrierJumpSerializer) Serialize(jump entities.CarrierJump) interface{} {
+	obj := &JsonObj{
+		"id":          jump.ID,
+		"carrierId":   jump.CarrierID,
+		"origin":      jump.Origin,
+		"destination": jump.Destination,
+		"departureAt": jump.DepartureAt,
+		"note":        jump.Note,
+		"status":      jump.Status,
+		"createdById": jump.CreatedByID,
+	}
+	return obj
+}
@@ -4,12 +4,15 @@ import (
 	"ruehrstaat-backend/db/entities"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type CarrierSerializer struct {
 	// Whether to include the full user object (true) or just specific fields
 	Full    bool `json:"full"`
 	Limited bool `json:"limited"`
+	// Commanders aboard per carrier, only shown in the limited view
+	Aboard map[uuid.UUID]int `json:"-"`
 }
 
 func (s *CarrierSerializer) Serialize(carrier entities.Carrier) interface{} {
@@ -31,6 +34,18 @@ func (s *CarrierSerializer) Serialize(carrier entities.Carrier) interface{} {
 		}
 	}
 
+	if s.Limited && s.Aboard != nil {
+		obj.Add("aboard", s.Aboard[carrier.ID])
+	}
+
+	if !s.Limited {
+		obj.Add("isPublic", carrier.IsPublic)
+		obj.Add("showFuelOnBadge", carrier.ShowFuelOnBadge)
+		obj.Add("publishToEddn", carrier.PublishToEddn)
+		obj.Add("syncToInara", carrier.SyncToInara)
+		obj.Add("syncToEdsm", carrier.SyncToEdsm)
+	}
+
 	if s.Full {
 		obj.Add("fuelLevel", carrier.FuelLevel)
 		obj.Add("cargoSpace", carrier.CargoSpace)
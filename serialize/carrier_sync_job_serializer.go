@@ -0,0 +1,22 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type CarrierSyncJobSerializer struct {
+}
+
+func (s *CarrierSyncJobSerializer) Serialize(job entities.CarrierSyncJob) interface{} {
+	return &JsonObj{
+		"id":            job.ID,
+		"target":        job.Target,
+		"event":         job.Event,
+		"starSystem":    job.StarSystem,
+		"occurredAt":    job.OccurredAt,
+		"status":        job.Status,
+		"attempts":      job.Attempts,
+		"nextAttemptAt": job.NextAttemptAt,
+		"lastError":     job.LastError,
+	}
+}
@@ -0,0 +1,85 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/services/construction"
+)
+
+type ConstructionSiteSerializer struct {
+	// Whether to include requirements and linked carriers
+	Full bool `json:"full"`
+}
+
+func (s *ConstructionSiteSerializer) Serialize(site entities.ConstructionSite) interface{} {
+	obj := &JsonObj{
+		"id":          site.ID,
+		"marketId":    site.MarketID,
+		"system":      site.System,
+		"name":        site.Name,
+		"progress":    site.Progress,
+		"isComplete":  site.IsComplete,
+		"isFailed":    site.IsFailed,
+		"createdById": site.CreatedByID,
+		"updatedAt":   site.UpdatedAt,
+	}
+
+	if s.Full {
+		obj.Add("requirements", DoArray[entities.ConstructionRequirement](&ConstructionRequirementSerializer{}, site.Requirements))
+		obj.Add("carriers", DoArray[entities.Carrier](&CarrierSerializer{Limited: true}, site.Carriers))
+	}
+	return obj
+}
+
+type ConstructionRequirementSerializer struct {
+}
+
+func (s *ConstructionRequirementSerializer) Serialize(requirement entities.ConstructionRequirement) interface{} {
+	obj := &JsonObj{
+		"commodity":      requirement.Commodity,
+		"label":          requirement.Label,
+		"requiredAmount": requirement.RequiredAmount,
+		"providedAmount": requirement.ProvidedAmount,
+		"remaining":      requirement.Remaining(),
+	}
+	return obj
+}
+
+type ConstructionSummarySerializer struct {
+}
+
+func (s *ConstructionSummarySerializer) Serialize(summary construction.Summary) interface{} {
+	commodities := []interface{}{}
+	for _, commodity := range summary.Commodities {
+		carriers := []interface{}{}
+		for _, stock := range commodity.Carriers {
+			carriers = append(carriers, &JsonObj{
+				"id":       stock.Carrier.ID,
+				"name":     stock.Carrier.Name,
+				"callsign": stock.Carrier.Callsign,
+				"location": stock.Carrier.CurrentLocation,
+				"quantity": stock.Quantity,
+			})
+		}
+
+		commodities = append(commodities, &JsonObj{
+			"commodity":  commodity.Commodity,
+			"label":      commodity.Label,
+			"required":   commodity.Required,
+			"provided":   commodity.Provided,
+			"remaining":  commodity.Remaining,
+			"onCarriers": commodity.OnCarriers,
+			"carriers":   carriers,
+		})
+	}
+
+	obj := &JsonObj{
+		"site":        Do[entities.ConstructionSite](&ConstructionSiteSerializer{}, *summary.Site),
+		"required":    summary.Required,
+		"provided":    summary.Provided,
+		"remaining":   summary.Remaining,
+		"onCarriers":  summary.OnCarriers,
+		"isCovered":   summary.IsCovered(),
+		"commodities": commodities,
+	}
+	return obj
+}
@@ -0,0 +1,17 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type FrontierLinkSerializer struct {
+}
+
+func (s *FrontierLinkSerializer) Serialize(link entities.FrontierLink) interface{} {
+	return &JsonObj{
+		"customerId":    link.CustomerID,
+		"linkedAt":      link.CreatedAt,
+		"lastSyncAt":    link.LastSyncAt,
+		"lastSyncError": link.LastSyncError,
+	}
+}
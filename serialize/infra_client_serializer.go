@@ -0,0 +1,29 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"time"
+)
+
+type InfraClientSerializer struct {
+}
+
+func (s *InfraClientSerializer) Serialize(client entities.InfraToken) interface{} {
+	obj := &JsonObj{
+		"id":              client.ID,
+		"name":            client.Name,
+		"scopes":          client.Scopes,
+		"isRevoked":       client.IsRevoked,
+		"createdAt":       client.CreatedAt,
+		"secretRotatedAt": client.SecretRotatedAt,
+		"lastUsedAt":      client.LastUsedAt,
+		"redirectUris":    client.RedirectURIs,
+		"oauthScopes":     client.OAuthScopes,
+		"isPublicClient":  client.IsPublicClient,
+	}
+
+	if client.PreviousSecretExpiresAt != nil && client.PreviousSecretExpiresAt.After(time.Now()) {
+		obj.Add("previousSecretExpiresAt", client.PreviousSecretExpiresAt)
+	}
+	return obj
+}
@@ -0,0 +1,38 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+
+	"github.com/google/uuid"
+)
+
+type JumpProposalSerializer struct {
+	// Optional user to report "hasVoted" for
+	UserID *uuid.UUID `json:"userId"`
+}
+
+func (s *JumpProposalSerializer) Serialize(proposal entities.JumpProposal) interface{} {
+	obj := &JsonObj{
+		"id":           proposal.ID,
+		"carrierId":    proposal.CarrierID,
+		"proposedById": proposal.ProposedByID,
+		"destination":  proposal.Destination,
+		"departureAt":  proposal.DepartureAt,
+		"note":         proposal.Note,
+		"votingEndsAt": proposal.VotingEndsAt,
+		"votingOpen":   proposal.IsVotingOpen(),
+		"status":       proposal.Status,
+		"jumpId":       proposal.JumpID,
+		"votes":        len(proposal.Votes),
+		"createdAt":    proposal.CreatedAt,
+	}
+
+	if proposal.ProposedBy != nil {
+		obj.Add("proposedBy", proposal.ProposedBy.CmdrName)
+	}
+
+	if s.UserID != nil {
+		obj.Add("hasVoted", proposal.HasVoted(*s.UserID))
+	}
+	return obj
+}
@@ -0,0 +1,36 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type LogisticsRequestSerializer struct {
+}
+
+func (s *LogisticsRequestSerializer) Serialize(request entities.LogisticsRequest) interface{} {
+	obj := &JsonObj{
+		"id":                request.ID,
+		"type":              request.Type,
+		"commodity":         request.Commodity,
+		"quantity":          request.Quantity,
+		"destinationSystem": request.DestinationSystem,
+		"deadline":          request.Deadline,
+		"note":              request.Note,
+		"status":            request.Status,
+		"response":          request.Response,
+		"requesterId":       request.RequesterID,
+		"carrierId":         request.CarrierID,
+		"handledById":       request.HandledByID,
+		"createdAt":         request.CreatedAt,
+		"updatedAt":         request.UpdatedAt,
+	}
+
+	if request.Requester != nil {
+		obj.Add("requester", request.Requester.CmdrName)
+	}
+
+	if request.Carrier != nil {
+		obj.Add("carrier", Do[entities.Carrier](&CarrierSerializer{Limited: true}, *request.Carrier))
+	}
+	return obj
+}
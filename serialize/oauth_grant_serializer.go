@@ -0,0 +1,24 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type OAuthGrantSerializer struct {
+}
+
+func (s *OAuthGrantSerializer) Serialize(grant entities.OAuthGrant) interface{} {
+	obj := &JsonObj{
+		"id":              grant.ID,
+		"clientId":        grant.ClientID,
+		"scopes":          grant.Scopes,
+		"createdAt":       grant.CreatedAt,
+		"lastRefreshedAt": grant.LastRefreshedAt,
+		"isActive":        grant.IsActive(),
+	}
+
+	if grant.Client != nil {
+		obj.Add("clientName", grant.Client.Name)
+	}
+	return obj
+}
@@ -0,0 +1,72 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/services/operations"
+)
+
+type OperationSerializer struct {
+	// Whether to include who RSVP'd, otherwise only the counts are included
+	Full bool `json:"full"`
+}
+
+func (s *OperationSerializer) Serialize(operation entities.Operation) interface{} {
+	obj := &JsonObj{
+		"id":            operation.ID,
+		"title":         operation.Title,
+		"description":   operation.Description,
+		"category":      operation.Category,
+		"startsAt":      operation.StartsAt,
+		"endsAt":        operation.EndsAt,
+		"targetSystems": operation.TargetSystems,
+		"isPublic":      operation.IsPublic,
+		"carriers":      DoArray[entities.Carrier](&CarrierSerializer{Limited: true}, operation.Carriers),
+		"rsvps": &JsonObj{
+			"going":    operation.CountRsvps(entities.OperationRsvpGoing),
+			"maybe":    operation.CountRsvps(entities.OperationRsvpMaybe),
+			"declined": operation.CountRsvps(entities.OperationRsvpDeclined),
+		},
+	}
+
+	if s.Full {
+		obj.Add("createdById", operation.CreatedByID)
+		obj.Add("attendees", DoArray[entities.OperationRsvp](&OperationRsvpSerializer{}, operation.Rsvps))
+	}
+	return obj
+}
+
+type OperationRsvpSerializer struct {
+}
+
+func (s *OperationRsvpSerializer) Serialize(rsvp entities.OperationRsvp) interface{} {
+	obj := &JsonObj{
+		"userId":    rsvp.UserID,
+		"status":    rsvp.Status,
+		"note":      rsvp.Note,
+		"updatedAt": rsvp.UpdatedAt,
+	}
+
+	if rsvp.User != nil {
+		obj.Add("cmdrName", rsvp.User.CmdrName)
+	}
+	return obj
+}
+
+type OperationTimelineSerializer struct {
+}
+
+func (s *OperationTimelineSerializer) Serialize(entry operations.TimelineEntry) interface{} {
+	obj := &JsonObj{
+		"type": entry.Type,
+		"at":   entry.At,
+	}
+
+	if entry.Carrier != nil {
+		obj.Add("carrier", Do[entities.Carrier](&CarrierSerializer{Limited: true}, *entry.Carrier))
+	}
+
+	if entry.Jump != nil {
+		obj.Add("jump", Do[entities.CarrierJump](&CarrierJumpSerializer{}, *entry.Jump))
+	}
+	return obj
+}
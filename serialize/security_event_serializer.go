@@ -0,0 +1,18 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+type SecurityEventSerializer struct{}
+
+func (s *SecurityEventSerializer) Serialize(event entities.SecurityEvent) interface{} {
+	return &JsonObj{
+		"id":        event.ID,
+		"type":      event.Type,
+		"ip":        event.IP,
+		"userAgent": event.UserAgent,
+		"details":   event.Details,
+		"createdAt": event.CreatedAt,
+	}
+}
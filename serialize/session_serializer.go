@@ -0,0 +1,24 @@
+package serialize
+
+import (
+	"ruehrstaat-backend/db/entities"
+
+	"github.com/google/uuid"
+)
+
+type SessionSerializer struct {
+	// Session of the request, flagged so clients can tell which device they are
+	CurrentID uuid.UUID
+}
+
+func (s *SessionSerializer) Serialize(session entities.RefreshToken) interface{} {
+	return &JsonObj{
+		"id":          session.ID,
+		"loginMethod": session.LoginMethod,
+		"ip":          session.IP,
+		"userAgent":   session.UserAgent,
+		"createdAt":   session.CreatedAt,
+		"lastUsedAt":  session.LastUsedAt,
+		"current":     session.ID == s.CurrentID,
+	}
+}
@@ -21,6 +21,9 @@ func (s *UserSerializer) Serialize(user entities.User) interface{} {
 		"isTotpJustActive": user.OtpActive && !user.OtpVerified,
 		"hasTotp":          user.HasTwoFactor(),
 		"linkedDiscord":    user.DiscordName,
+		"hidePresence":     user.HidePresence,
+		"hasInaraApiKey":   user.InaraApiKey != nil,
+		"hasEdsmApiKey":    user.EdsmApiKey != nil,
 	}
 	if s.Full {
 		obj.Add("isAdmin", user.IsAdmin)
@@ -0,0 +1,121 @@
+package badge
+
+import (
+	"fmt"
+	"image/color"
+	"ruehrstaat-backend/db/entities"
+	"strings"
+)
+
+const (
+	Width  = 400
+	Height = 110
+
+	// Longest text that fits into a badge line
+	maxLineLength = 34
+)
+
+type Theme struct {
+	Name       string
+	Background color.RGBA
+	Border     color.RGBA
+	Text       color.RGBA
+	Muted      color.RGBA
+	Accent     color.RGBA
+	BarEmpty   color.RGBA
+}
+
+var Themes = map[string]Theme{
+	"dark": {
+		Name:       "dark",
+		Background: color.RGBA{0x1b, 0x1d, 0x23, 0xff},
+		Border:     color.RGBA{0x3a, 0x3d, 0x46, 0xff},
+		Text:       color.RGBA{0xf2, 0xf2, 0xf2, 0xff},
+		Muted:      color.RGBA{0xa0, 0xa4, 0xae, 0xff},
+		Accent:     color.RGBA{0xf0, 0xa3, 0x0a, 0xff},
+		BarEmpty:   color.RGBA{0x3a, 0x3d, 0x46, 0xff},
+	},
+	"light": {
+		Name:       "light",
+		Background: color.RGBA{0xfa, 0xfa, 0xfa, 0xff},
+		Border:     color.RGBA{0xd0, 0xd3, 0xd9, 0xff},
+		Text:       color.RGBA{0x1b, 0x1d, 0x23, 0xff},
+		Muted:      color.RGBA{0x5c, 0x60, 0x6a, 0xff},
+		Accent:     color.RGBA{0xc7, 0x7c, 0x00, 0xff},
+		BarEmpty:   color.RGBA{0xe2, 0xe4, 0xe8, 0xff},
+	},
+}
+
+const DefaultTheme = "dark"
+
+// Returns the theme with the given name, falling back to the default theme.
+func GetTheme(name string) Theme {
+	if theme, ok := Themes[name]; ok {
+		return theme
+	}
+	return Themes[DefaultTheme]
+}
+
+// Everything that is shown on a badge.
+type Data struct {
+	Name          string
+	Callsign      string
+	System        string
+	DockingAccess string
+	ShowFuel      bool
+	FuelLevel     int // 0 - 1000
+}
+
+func NewData(cr *entities.Carrier) Data {
+	return Data{
+		Name:          truncate(cr.Name, maxLineLength-len(cr.Callsign)-3),
+		Callsign:      cr.Callsign,
+		System:        truncate(cr.CurrentLocation, maxLineLength-8),
+		DockingAccess: dockingAccessLabels[cr.DockingAccess],
+		ShowFuel:      cr.ShowFuelOnBadge,
+		FuelLevel:     clamp(cr.FuelLevel, 0, 1000),
+	}
+}
+
+func (d Data) systemLine() string {
+	return "System: " + d.System
+}
+
+func (d Data) dockingLine() string {
+	return "Docking: " + d.DockingAccess
+}
+
+func (d Data) fuelLine() string {
+	return fmt.Sprintf("Fuel: %d / 1000 t", d.FuelLevel)
+}
+
+// Share of the fuel tank that is filled, between 0 and 1.
+func (d Data) fuelRatio() float64 {
+	return float64(d.FuelLevel) / 1000
+}
+
+var dockingAccessLabels = map[entities.CarrierDockingAccess]string{
+	entities.DockingAccessAll:                "All",
+	entities.DockingAccessNone:               "None",
+	entities.DockingAccessFriends:            "Friends",
+	entities.DockingAccessSquadron:           "Squadron",
+	entities.DockingAccessSquadronAndFriends: "Squadron & Friends",
+}
+
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if length < 1 || len(runes) <= length {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:length-1])) + "…"
+}
+
+func clamp(value int, min int, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,126 @@
+package badge
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sync"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+type fontFaces struct {
+	title    font.Face
+	callsign font.Face
+	body     font.Face
+	small    font.Face
+}
+
+type parsedFonts struct {
+	regular *opentype.Font
+	bold    *opentype.Font
+	mono    *opentype.Font
+}
+
+var (
+	fonts     *parsedFonts
+	fontsErr  error
+	fontsOnce sync.Once
+)
+
+// Parsed fonts are shared, faces are not safe for concurrent use, so every render creates its own.
+func loadFaces() (*fontFaces, error) {
+	fontsOnce.Do(func() {
+		regular, err := opentype.Parse(goregular.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		bold, err := opentype.Parse(gobold.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+		mono, err := opentype.Parse(gomono.TTF)
+		if err != nil {
+			fontsErr = err
+			return
+		}
+
+		fonts = &parsedFonts{regular: regular, bold: bold, mono: mono}
+	})
+	if fontsErr != nil {
+		return nil, fontsErr
+	}
+
+	var faceErr error
+	newFace := func(f *opentype.Font, size float64) font.Face {
+		face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil && faceErr == nil {
+			faceErr = err
+		}
+		return face
+	}
+
+	faces := &fontFaces{
+		title:    newFace(fonts.bold, 17),
+		callsign: newFace(fonts.mono, 14),
+		body:     newFace(fonts.regular, 13),
+		small:    newFace(fonts.regular, 12),
+	}
+	return faces, faceErr
+}
+
+// Rasterizes the badge with the same layout as the SVG version.
+func RenderPNG(data Data, theme Theme) ([]byte, error) {
+	f, err := loadFaces()
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{theme.Border}, image.Point{}, draw.Src)
+	fillRect(img, image.Rect(1, 1, Width-1, Height-1), theme.Background)
+	fillRect(img, image.Rect(1, 1, 7, Height-1), theme.Accent)
+
+	drawText(img, f.title, theme.Text, 20, 30, data.Name)
+	callsignWidth := font.MeasureString(f.callsign, data.Callsign).Ceil()
+	drawText(img, f.callsign, theme.Accent, Width-16-callsignWidth, 30, data.Callsign)
+	drawText(img, f.body, theme.Muted, 20, 56, data.systemLine())
+	drawText(img, f.body, theme.Muted, 20, 78, data.dockingLine())
+
+	if data.ShowFuel {
+		barWidth := Width - 220
+		drawText(img, f.small, theme.Muted, 20, 100, data.fuelLine())
+		fillRect(img, image.Rect(200, 91, 200+barWidth, 101), theme.BarEmpty)
+		fillRect(img, image.Rect(200, 91, 200+int(float64(barWidth)*data.fuelRatio()), 101), theme.Accent)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := png.Encode(buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func fillRect(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	draw.Draw(img, rect, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+func drawText(img *image.RGBA, face font.Face, c color.RGBA, x int, y int, text string) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	drawer.DrawString(text)
+}
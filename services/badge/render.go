@@ -0,0 +1,54 @@
+package badge
+
+import (
+	"context"
+	"fmt"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/logging"
+	"time"
+)
+
+var log = logging.Logger{Package: "badge"}
+
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+const cacheDuration = time.Hour * 24
+
+// Renders the badge of the carrier in the given format. Rendered badges are cached,
+// the cache key contains the carrier's UpdatedAt so every change produces a new badge.
+func Render(cr *entities.Carrier, format string, theme Theme) ([]byte, error) {
+	key := CacheKey(cr, format, theme)
+
+	if cached, err := cache.Redis.Get(context.Background(), "badge:"+key).Bytes(); err == nil {
+		return cached, nil
+	}
+
+	data := NewData(cr)
+
+	var rendered []byte
+	var err error
+	switch format {
+	case FormatPNG:
+		rendered, err = RenderPNG(data, theme)
+	default:
+		rendered, err = RenderSVG(data, theme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cache.Redis.Set(context.Background(), "badge:"+key, rendered, cacheDuration).Err(); err != nil {
+		log.Println("Failed to cache badge:", err)
+	}
+
+	return rendered, nil
+}
+
+// Identifies a rendered badge, also usable as ETag.
+func CacheKey(cr *entities.Carrier, format string, theme Theme) string {
+	return fmt.Sprintf("%s:%d:%s:%s", cr.ID, cr.UpdatedAt.UnixNano(), theme.Name, format)
+}
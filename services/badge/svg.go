@@ -0,0 +1,58 @@
+package badge
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image/color"
+)
+
+var svgTemplate = template.Must(template.New("badge").Funcs(template.FuncMap{
+	"hex": hexColor,
+}).Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img" aria-label="{{.Data.Name}} ({{.Data.Callsign}})">
+  <title>{{.Data.Name}} ({{.Data.Callsign}})</title>
+  <rect x="0.5" y="0.5" width="{{.InnerWidth}}" height="{{.InnerHeight}}" rx="8" fill="{{hex .Theme.Background}}" stroke="{{hex .Theme.Border}}"/>
+  <rect x="0.5" y="0.5" width="6" height="{{.InnerHeight}}" rx="3" fill="{{hex .Theme.Accent}}"/>
+  <g font-family="Verdana, DejaVu Sans, sans-serif">
+    <text x="20" y="30" font-size="17" font-weight="bold" fill="{{hex .Theme.Text}}">{{.Data.Name}}</text>
+    <text x="{{.CallsignX}}" y="30" font-size="14" font-family="monospace" text-anchor="end" fill="{{hex .Theme.Accent}}">{{.Data.Callsign}}</text>
+    <text x="20" y="56" font-size="13" fill="{{hex .Theme.Muted}}">{{.SystemLine}}</text>
+    <text x="20" y="78" font-size="13" fill="{{hex .Theme.Muted}}">{{.DockingLine}}</text>
+{{- if .Data.ShowFuel}}
+    <text x="20" y="100" font-size="12" fill="{{hex .Theme.Muted}}">{{.FuelLine}}</text>
+    <rect x="200" y="91" width="{{.BarWidth}}" height="10" rx="3" fill="{{hex .Theme.BarEmpty}}"/>
+    <rect x="200" y="91" width="{{.FuelWidth}}" height="10" rx="3" fill="{{hex .Theme.Accent}}"/>
+{{- end}}
+  </g>
+</svg>
+`))
+
+// Renders the badge as SVG, html/template takes care of escaping the carrier data.
+func RenderSVG(data Data, theme Theme) ([]byte, error) {
+	barWidth := Width - 220
+
+	buf := &bytes.Buffer{}
+	err := svgTemplate.Execute(buf, map[string]interface{}{
+		"Width":       Width,
+		"Height":      Height,
+		"InnerWidth":  Width - 1,
+		"InnerHeight": Height - 1,
+		"CallsignX":   Width - 16,
+		"BarWidth":    barWidth,
+		"FuelWidth":   fmt.Sprintf("%.1f", float64(barWidth)*data.fuelRatio()),
+		"SystemLine":  data.systemLine(),
+		"DockingLine": data.dockingLine(),
+		"FuelLine":    data.fuelLine(),
+		"Data":        data,
+		"Theme":       theme,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,121 @@
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+	"time"
+
+	"github.com/google/uuid"
+	jsoniter "github.com/json-iterator/go"
+)
+
+var log = logging.Logger{Package: "calendar"}
+
+// How long a jump is shown in the calendar
+const jumpDuration = time.Minute * 15
+
+const cacheDuration = time.Hour * 24
+
+// Builds the feed of all scheduled jumps of the given carriers.
+func BuildCarriersFeed(name string, carriers []entities.Carrier) (string, *errors.RstError) {
+	events := []Event{}
+	for _, cr := range carriers {
+		carrierEvents, err := carrierEvents(cr)
+		if err != nil {
+			return "", err
+		}
+		events = append(events, carrierEvents...)
+	}
+
+	return Build(name, events), nil
+}
+
+// Returns the events of all scheduled jumps of the carrier. Events are cached per carrier and
+// the cache key changes whenever jump data of the carrier changes, so the feed is regenerated
+// on the next request after a change.
+func carrierEvents(cr entities.Carrier) ([]Event, *errors.RstError) {
+	version, err := jumpsVersion(cr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("calendar:carrier:%s:%s:%d", cr.ID, version, cr.UpdatedAt.UnixNano())
+
+	if cached, err := cache.Redis.Get(context.Background(), key).Result(); err == nil {
+		events := []Event{}
+		if err := jsoniter.UnmarshalFromString(cached, &events); err == nil {
+			return events, nil
+		}
+	}
+
+	jumps := []entities.CarrierJump{}
+	if res := db.DB.Where("carrier_id = ? AND status IN ?", cr.ID, []entities.CarrierJumpStatus{entities.CarrierJumpStatusPending, entities.CarrierJumpStatusPlanned}).Order("departure_at").Find(&jumps); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+
+	events := make([]Event, len(jumps))
+	for i, jump := range jumps {
+		events[i] = jumpEvent(cr, jump)
+	}
+
+	if data, err := jsoniter.MarshalToString(events); err == nil {
+		if err := cache.Redis.Set(context.Background(), key, data, cacheDuration).Err(); err != nil {
+			log.Println("Failed to cache calendar events:", err)
+		}
+	}
+
+	return events, nil
+}
+
+// Fingerprint of the jump data of a carrier, changes whenever a jump is added or updated.
+func jumpsVersion(carrierID uuid.UUID) (string, *errors.RstError) {
+	result := struct {
+		Count     int64
+		UpdatedAt *time.Time
+	}{}
+
+	if res := db.DB.Unscoped().Model(&entities.CarrierJump{}).Select("COUNT(*) AS count, MAX(updated_at) AS updated_at").Where("carrier_id = ?", carrierID).Scan(&result); res.Error != nil {
+		return "", errors.NewDBErrorFromError(res.Error)
+	}
+
+	if result.UpdatedAt == nil {
+		return "0", nil
+	}
+
+	return fmt.Sprintf("%d-%d", result.Count, result.UpdatedAt.UnixNano()), nil
+}
+
+func jumpEvent(cr entities.Carrier, jump entities.CarrierJump) Event {
+	description := fmt.Sprintf("From: %s\nTo: %s", valueOrUnknown(jump.Origin), jump.Destination)
+	if jump.Note != "" {
+		description += "\n\n" + jump.Note
+	}
+
+	status := "CONFIRMED"
+	if jump.Status == entities.CarrierJumpStatusPlanned {
+		status = "TENTATIVE"
+	}
+
+	return Event{
+		UID:         jump.ID.String() + "@ruehrstaat.de",
+		Summary:     fmt.Sprintf("%s (%s) jumps to %s", cr.Name, cr.Callsign, jump.Destination),
+		Description: description,
+		Location:    jump.Destination,
+		Start:       jump.DepartureAt,
+		End:         jump.DepartureAt.Add(jumpDuration),
+		Status:      status,
+		Modified:    jump.UpdatedAt,
+	}
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
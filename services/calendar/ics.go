@@ -0,0 +1,100 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// Maximum line length in octets before a line has to be folded (RFC 5545 3.1)
+const icsMaxLineLength = 75
+
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Status      string // TENTATIVE, CONFIRMED or CANCELLED
+	Modified    time.Time
+}
+
+// Writes a VCALENDAR containing the given events.
+func Build(name string, events []Event) string {
+	b := &strings.Builder{}
+
+	writeLine(b, "BEGIN:VCALENDAR")
+	writeLine(b, "VERSION:2.0")
+	writeLine(b, "PRODID:-//Ruehrstaat Squadron//Carrier Jumps//EN")
+	writeLine(b, "CALSCALE:GREGORIAN")
+	writeLine(b, "METHOD:PUBLISH")
+	writeLine(b, "X-WR-CALNAME:"+escape(name))
+
+	for _, event := range events {
+		writeEvent(b, event)
+	}
+
+	writeLine(b, "END:VCALENDAR")
+
+	return b.String()
+}
+
+func writeEvent(b *strings.Builder, event Event) {
+	writeLine(b, "BEGIN:VEVENT")
+	writeLine(b, "UID:"+escape(event.UID))
+	writeLine(b, "DTSTAMP:"+event.Modified.UTC().Format(icsTimeFormat))
+	writeLine(b, "LAST-MODIFIED:"+event.Modified.UTC().Format(icsTimeFormat))
+	writeLine(b, "DTSTART:"+event.Start.UTC().Format(icsTimeFormat))
+	writeLine(b, "DTEND:"+event.End.UTC().Format(icsTimeFormat))
+	writeLine(b, "SUMMARY:"+escape(event.Summary))
+	if event.Description != "" {
+		writeLine(b, "DESCRIPTION:"+escape(event.Description))
+	}
+	if event.Location != "" {
+		writeLine(b, "LOCATION:"+escape(event.Location))
+	}
+	if event.Status != "" {
+		writeLine(b, "STATUS:"+event.Status)
+	}
+	writeLine(b, "END:VEVENT")
+}
+
+// Writes a content line, folding it into multiple lines if it exceeds the maximum length.
+// Lines are never split inside a multi-byte UTF-8 character.
+func writeLine(b *strings.Builder, line string) {
+	limit := icsMaxLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+
+		// continuation lines start with a space which counts towards the limit
+		limit = icsMaxLineLength - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+var icsEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	";", "\\;",
+	",", "\\,",
+	"\r\n", "\\n",
+	"\n", "\\n",
+)
+
+func escape(s string) string {
+	return icsEscaper.Replace(s)
+}
@@ -0,0 +1,81 @@
+package capi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const defaultCapiUrl = "https://companion.orerve.net"
+
+var (
+	// The access token was rejected, the user has to link their account again
+	ErrUnauthorized = errors.New("Frontier rejected the access token")
+	// The commander does not own a fleet carrier
+	ErrNoCarrier = errors.New("Commander does not own a fleet carrier")
+)
+
+// Access to the Companion API, implemented by a mock in tests.
+type Client interface {
+	FleetCarrier(accessToken string) (*FleetCarrier, error)
+}
+
+type HTTPClient struct {
+	BaseURL string
+	HTTP    *http.Client
+}
+
+// Client for the url in FRONTIER_CAPI_URL, defaults to the live Companion API.
+func NewClient() *HTTPClient {
+	url := os.Getenv("FRONTIER_CAPI_URL")
+	if url == "" {
+		url = defaultCapiUrl
+	}
+
+	return &HTTPClient{
+		BaseURL: strings.TrimSuffix(url, "/"),
+		HTTP:    &http.Client{Timeout: time.Second * 30},
+	}
+}
+
+func (c *HTTPClient) FleetCarrier(accessToken string) (*FleetCarrier, error) {
+	req, err := http.NewRequest(http.MethodGet, c.BaseURL+"/fleetcarrier", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	res, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNoContent:
+		return nil, ErrNoCarrier
+	case http.StatusUnauthorized:
+		return nil, ErrUnauthorized
+	default:
+		return nil, fmt.Errorf("Companion API responded with %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := &FleetCarrier{}
+	if err := jsoniter.Unmarshal(body, fc); err != nil {
+		return nil, err
+	}
+
+	return fc, nil
+}
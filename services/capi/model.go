@@ -0,0 +1,165 @@
+package capi
+
+import (
+	"encoding/hex"
+	"ruehrstaat-backend/db/entities"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Response of the /fleetcarrier endpoint, reduced to the fields that are synced.
+type FleetCarrier struct {
+	Name struct {
+		Callsign string `json:"callsign"`
+		// hex encoded carrier name
+		VanityName string `json:"vanityName"`
+	} `json:"name"`
+	CurrentStarSystem string `json:"currentStarSystem"`
+	// fuel level as a string, e.g. "785"
+	Fuel            jsoniter.Number `json:"fuel"`
+	DockingAccess   string          `json:"dockingAccess"`
+	NotoriousAccess bool            `json:"notoriousAccess"`
+	Balance         int64           `json:"balance"`
+
+	Capacity struct {
+		CargoForSale       int `json:"cargoForSale"`
+		CargoNotForSale    int `json:"cargoNotForSale"`
+		CargoSpaceReserved int `json:"cargoSpaceReserved"`
+		FreeSpace          int `json:"freeSpace"`
+	} `json:"capacity"`
+
+	Finance struct {
+		BankBalance         int64 `json:"bankBalance"`
+		BankReservedBalance int64 `json:"bankReservedBalance"`
+	} `json:"finance"`
+
+	Market struct {
+		ID int64 `json:"id"`
+		// service name (lowercase) to state, e.g. "ok", "unavailable" or "unmanned"
+		Services map[string]string `json:"services"`
+	} `json:"market"`
+
+	Cargo []struct {
+		Commodity string `json:"commodity"`
+		Quantity  int    `json:"qty"`
+		Stolen    bool   `json:"stolen"`
+		Mission   bool   `json:"mission"`
+	} `json:"cargo"`
+
+	Itinerary struct {
+		Completed []ItineraryEntry `json:"completed"`
+		// jump currently plotted ingame, null if there is none
+		CurrentJump *PlannedJump `json:"currentJump"`
+	} `json:"itinerary"`
+}
+
+// A system the carrier visited, departure is unset for the system it is still in.
+type ItineraryEntry struct {
+	StarSystem    string   `json:"starsystem"`
+	DepartureTime CapiTime `json:"departureTime"`
+	ArrivalTime   CapiTime `json:"arrivalTime"`
+	State         string   `json:"state"`
+}
+
+type PlannedJump struct {
+	StarSystem    string   `json:"starsystem"`
+	DepartureTime CapiTime `json:"departureTime"`
+}
+
+// Companion API timestamps look like "2025-03-14 18:02:09" and are in UTC.
+type CapiTime struct {
+	time.Time
+}
+
+const capiTimeLayout = "2006-01-02 15:04:05"
+
+func (t *CapiTime) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	if value == "" || value == "null" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	parsed, err := time.ParseInLocation(capiTimeLayout, value, time.UTC)
+	if err != nil {
+		// be lenient in case the format ever changes to RFC 3339
+		parsed, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			return err
+		}
+	}
+	t.Time = parsed
+	return nil
+}
+
+// Decoded carrier name, falls back to the raw value if it is not hex encoded.
+func (fc *FleetCarrier) CarrierName() string {
+	name, err := hex.DecodeString(fc.Name.VanityName)
+	if err != nil {
+		return fc.Name.VanityName
+	}
+	return string(name)
+}
+
+func (fc *FleetCarrier) FuelLevel() int {
+	fuel, err := strconv.Atoi(fc.Fuel.String())
+	if err != nil {
+		return 0
+	}
+	return fuel
+}
+
+// Cargo space usable for commodities, i.e. without the space taken by installed services and packs.
+func (fc *FleetCarrier) CargoSpace() int {
+	return fc.Capacity.CargoForSale + fc.Capacity.CargoNotForSale + fc.Capacity.CargoSpaceReserved + fc.Capacity.FreeSpace
+}
+
+// Star system the carrier is located in. Uses the last arrival of the itinerary if the current system is missing.
+func (fc *FleetCarrier) StarSystem() string {
+	if fc.CurrentStarSystem != "" {
+		return fc.CurrentStarSystem
+	}
+
+	system := ""
+	var arrival time.Time
+	for _, entry := range fc.Itinerary.Completed {
+		if entry.StarSystem != "" && entry.ArrivalTime.After(arrival) {
+			system = entry.StarSystem
+			arrival = entry.ArrivalTime.Time
+		}
+	}
+	return system
+}
+
+// Jumps between the visited systems of the itinerary, oldest first. Each jump departs when the carrier left the previous system.
+func (fc *FleetCarrier) CompletedJumps() []entities.CarrierJump {
+	visits := []ItineraryEntry{}
+	for _, entry := range fc.Itinerary.Completed {
+		if entry.StarSystem != "" && !entry.ArrivalTime.IsZero() {
+			visits = append(visits, entry)
+		}
+	}
+	sort.SliceStable(visits, func(i, j int) bool {
+		return visits[i].ArrivalTime.Before(visits[j].ArrivalTime.Time)
+	})
+
+	jumps := []entities.CarrierJump{}
+	for i := 1; i < len(visits); i++ {
+		departure := visits[i-1].DepartureTime.Time
+		if departure.IsZero() {
+			departure = visits[i].ArrivalTime.Time
+		}
+
+		jumps = append(jumps, entities.CarrierJump{
+			Origin:      visits[i-1].StarSystem,
+			Destination: visits[i].StarSystem,
+			DepartureAt: departure,
+			Status:      entities.CarrierJumpStatusCompleted,
+		})
+	}
+	return jumps
+}
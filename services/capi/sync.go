@@ -0,0 +1,234 @@
+package capi
+
+import (
+	"fmt"
+	"ruehrstaat-backend/auth/frontier"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/services/carrier"
+	"ruehrstaat-backend/util"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+var log = logging.Logger{Package: "services/capi"}
+
+// Exchanges refresh tokens for access tokens, implemented by a mock in tests.
+type TokenRefresher interface {
+	Refresh(refreshToken string) (*oauth2.Token, error)
+}
+
+// Pulls fleet carrier data of linked Frontier accounts into the carriers owned by their users.
+type Syncer struct {
+	Tokens TokenRefresher
+	Client Client
+}
+
+var (
+	defaultSyncer     *Syncer
+	defaultSyncerOnce sync.Once
+)
+
+// Shared syncer using the Frontier OAuth configuration and the Companion API from the environment.
+func DefaultSyncer() *Syncer {
+	defaultSyncerOnce.Do(func() {
+		defaultSyncer = &Syncer{
+			Tokens: &frontier.OAuthRefresher{Conf: frontier.LinkingConf},
+			Client: NewClient(),
+		}
+	})
+	return defaultSyncer
+}
+
+// Syncs the carriers of all linked Frontier accounts, run by the cron system.
+func SyncCarriers() {
+	links := []entities.FrontierLink{}
+	if res := db.DB.Find(&links); res.Error != nil {
+		log.Println("Could not load Frontier links", res.Error)
+		return
+	}
+
+	syncer := DefaultSyncer()
+	for i := range links {
+		if err := syncer.SyncLink(&links[i]); err != nil {
+			log.Println("Could not sync carrier of user", links[i].UserID, err)
+		}
+	}
+}
+
+// Syncs the carrier of a single linked account and stores the outcome on the link.
+func (s *Syncer) SyncLink(link *entities.FrontierLink) error {
+	err := s.syncLink(link)
+
+	now := time.Now()
+	link.LastSyncAt = &now
+	link.LastSyncError = nil
+	if err != nil {
+		message := err.Error()
+		link.LastSyncError = &message
+	}
+
+	if res := db.DB.Model(link).Select("last_sync_at", "last_sync_error").Updates(link); res.Error != nil {
+		log.Println("Could not store sync result of Frontier link", link.ID, res.Error)
+	}
+
+	return err
+}
+
+func (s *Syncer) syncLink(link *entities.FrontierLink) error {
+	refreshToken, err := util.Decrypt(link.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	fc, token, fetchErr := s.fetchFleetCarrier(refreshToken)
+
+	// Frontier rotates refresh tokens, the old one stops working after the refresh
+	if token != nil && token.RefreshToken != refreshToken {
+		encrypted, err := util.Encrypt(token.RefreshToken)
+		if err != nil {
+			return err
+		}
+
+		link.RefreshToken = encrypted
+		if res := db.DB.Model(link).Update("refresh_token", encrypted); res.Error != nil {
+			return res.Error
+		}
+	}
+
+	if fetchErr != nil {
+		return fetchErr
+	}
+
+	cr := &entities.Carrier{}
+	res := db.DB.Where("UPPER(callsign) = UPPER(?) AND owner_id = ?", fc.Name.Callsign, link.UserID).Limit(1).Find(cr)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("Carrier %s is not registered for the linked user", fc.Name.Callsign)
+	}
+
+	return applyFleetCarrier(cr, fc)
+}
+
+// Refreshes the access token and fetches the carrier. The token is returned even if fetching fails, so a rotated
+// refresh token can still be stored.
+func (s *Syncer) fetchFleetCarrier(refreshToken string) (*FleetCarrier, *oauth2.Token, error) {
+	token, err := s.Tokens.Refresh(refreshToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Could not refresh Frontier token, the account may have to be linked again: %w", err)
+	}
+
+	fc, err := s.Client.FleetCarrier(token.AccessToken)
+	if err != nil {
+		return nil, token, err
+	}
+
+	return fc, token, nil
+}
+
+// Stores the Companion API state on the carrier and records the resulting activity.
+func applyFleetCarrier(cr *entities.Carrier, fc *FleetCarrier) error {
+	if system := fc.StarSystem(); system != "" {
+		if err := carrier.RecordObservedLocation(cr, system, system); err != nil {
+			return err
+		}
+	}
+
+	before := carrier.Snapshot(cr)
+
+	if name := fc.CarrierName(); name != "" {
+		cr.Name = name
+	}
+	cr.FuelLevel = fc.FuelLevel()
+	cr.CargoSpace = fc.CargoSpace()
+	cr.AllowNotorious = fc.NotoriousAccess
+
+	// an unknown docking access would fail the whole sync, keep the stored one instead
+	if fc.DockingAccess != "" {
+		if err := cr.SetDockingAccess(fc.DockingAccess); err != nil {
+			log.Println("Unknown docking access", fc.DockingAccess, "for carrier", cr.ID)
+		}
+	}
+
+	cr.Balance = fc.Finance.BankBalance
+	if cr.Balance == 0 {
+		cr.Balance = fc.Balance
+	}
+	cr.ReserveBalance = fc.Finance.BankReservedBalance
+	cr.AvailableBalance = cr.Balance - cr.ReserveBalance
+
+	for name, service := range entities.CarrierServices {
+		state, ok := fc.Market.Services[strings.ToLower(name)]
+		if !ok {
+			continue
+		}
+		if state == "ok" {
+			cr.AddService(service)
+		} else {
+			cr.RemoveService(service)
+		}
+	}
+
+	if res := db.DB.Save(cr); res.Error != nil {
+		return res.Error
+	}
+	carrier.RecordChanges(&before, cr)
+
+	cargo := []entities.CarrierCargo{}
+	for _, entry := range fc.Cargo {
+		cargo = append(cargo, entities.CarrierCargo{
+			Commodity: entry.Commodity,
+			Quantity:  entry.Quantity,
+			Stolen:    entry.Stolen,
+			Mission:   entry.Mission,
+		})
+	}
+
+	if _, err := carrier.ReplaceCargo(cr, cargo); err != nil {
+		return err
+	}
+
+	return applyItinerary(cr, fc)
+}
+
+// Stores the jumps of the itinerary and the jump currently plotted ingame.
+func applyItinerary(cr *entities.Carrier, fc *FleetCarrier) error {
+	// the itinerary reaches back months, only jumps after the latest known one are new
+	latest := entities.CarrierJump{}
+	res := db.DB.Where("carrier_id = ? AND status = ?", cr.ID, entities.CarrierJumpStatusCompleted).Order("departure_at DESC").Limit(1).Find(&latest)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	for _, jump := range fc.CompletedJumps() {
+		if res.RowsAffected > 0 && jump.DepartureAt.Before(latest.DepartureAt) {
+			continue
+		}
+		if err := carrier.RecordCompletedJump(cr, jump.Origin, jump.Destination, jump.DepartureAt); err != nil {
+			return err
+		}
+	}
+
+	planned := fc.Itinerary.CurrentJump
+	if planned == nil || planned.StarSystem == "" || planned.DepartureTime.Before(time.Now()) {
+		return nil
+	}
+
+	// recording the plotted jump again would complete the pending one
+	res = db.DB.Where("carrier_id = ? AND status = ? AND LOWER(destination) = LOWER(?)", cr.ID, entities.CarrierJumpStatusPending, planned.StarSystem).Limit(1).Find(&entities.CarrierJump{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+
+	_, err := carrier.RecordPlottedJump(cr, fc.StarSystem(), planned.StarSystem, planned.DepartureTime.Time)
+	return err
+}
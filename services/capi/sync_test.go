@@ -0,0 +1,143 @@
+package capi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"ruehrstaat-backend/db/entities"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type mockRefresher struct {
+	token *oauth2.Token
+	err   error
+	calls []string
+}
+
+func (r *mockRefresher) Refresh(refreshToken string) (*oauth2.Token, error) {
+	r.calls = append(r.calls, refreshToken)
+	return r.token, r.err
+}
+
+// Serves the recorded /fleetcarrier response to requests with the expected access token.
+func mockCompanionAPI(t *testing.T, accessToken string, status int) *httptest.Server {
+	t.Helper()
+
+	fixture, err := os.ReadFile("testdata/fleetcarrier.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/fleetcarrier" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write(fixture)
+		}
+	}))
+}
+
+func TestFetchFleetCarrier(t *testing.T) {
+	server := mockCompanionAPI(t, "access", http.StatusOK)
+	defer server.Close()
+
+	refresher := &mockRefresher{token: &oauth2.Token{AccessToken: "access", RefreshToken: "rotated"}}
+	syncer := &Syncer{Tokens: refresher, Client: &HTTPClient{BaseURL: server.URL, HTTP: server.Client()}}
+
+	fc, token, err := syncer.fetchFleetCarrier("refresh")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(refresher.calls) != 1 || refresher.calls[0] != "refresh" {
+		t.Fatalf("expected the stored refresh token to be exchanged, got %v", refresher.calls)
+	}
+	if token.RefreshToken != "rotated" {
+		t.Fatalf("expected the rotated refresh token, got %q", token.RefreshToken)
+	}
+
+	if fc.Name.Callsign != "K7Q-BQL" || fc.CarrierName() != "Rührstaat Supply" {
+		t.Errorf("unexpected carrier %q %q", fc.Name.Callsign, fc.CarrierName())
+	}
+	if fc.FuelLevel() != 785 || fc.CargoSpace() != 16842 || fc.StarSystem() != "Colonia" {
+		t.Errorf("unexpected state: fuel %d, cargo space %d, system %q", fc.FuelLevel(), fc.CargoSpace(), fc.StarSystem())
+	}
+
+	arrival := time.Date(2025, 3, 12, 9, 46, 52, 0, time.UTC)
+	last := fc.Itinerary.Completed[len(fc.Itinerary.Completed)-1]
+	if !last.ArrivalTime.Equal(arrival) || !last.DepartureTime.IsZero() {
+		t.Errorf("unexpected itinerary times %v %v", last.ArrivalTime, last.DepartureTime)
+	}
+
+	planned := fc.Itinerary.CurrentJump
+	if planned == nil || planned.StarSystem != "Sagittarius A*" || !planned.DepartureTime.Equal(time.Date(2099, 1, 1, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected current jump %+v", planned)
+	}
+}
+
+func TestFetchFleetCarrierErrors(t *testing.T) {
+	server := mockCompanionAPI(t, "access", http.StatusNoContent)
+	defer server.Close()
+	client := &HTTPClient{BaseURL: server.URL, HTTP: server.Client()}
+
+	// the token is still returned, a rotated refresh token has to be stored either way
+	syncer := &Syncer{Tokens: &mockRefresher{token: &oauth2.Token{AccessToken: "access", RefreshToken: "rotated"}}, Client: client}
+	if _, token, err := syncer.fetchFleetCarrier("refresh"); err != ErrNoCarrier || token == nil {
+		t.Errorf("expected ErrNoCarrier with token, got %v %v", err, token)
+	}
+
+	syncer = &Syncer{Tokens: &mockRefresher{token: &oauth2.Token{AccessToken: "expired"}}, Client: client}
+	if _, _, err := syncer.fetchFleetCarrier("refresh"); err != ErrUnauthorized {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+
+	refreshErr := errors.New("invalid_grant")
+	syncer = &Syncer{Tokens: &mockRefresher{err: refreshErr}, Client: client}
+	if _, token, err := syncer.fetchFleetCarrier("refresh"); !errors.Is(err, refreshErr) || token != nil {
+		t.Errorf("expected the refresh error, got %v %v", err, token)
+	}
+}
+
+func TestCompletedJumps(t *testing.T) {
+	server := mockCompanionAPI(t, "access", http.StatusOK)
+	defer server.Close()
+
+	fc, err := (&HTTPClient{BaseURL: server.URL, HTTP: server.Client()}).FleetCarrier("access")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []entities.CarrierJump{
+		{Origin: "Sol", Destination: "Deciat", DepartureAt: time.Date(2025, 3, 10, 20, 15, 0, 0, time.UTC), Status: entities.CarrierJumpStatusCompleted},
+		{Origin: "Deciat", Destination: "Colonia", DepartureAt: time.Date(2025, 3, 12, 9, 30, 5, 0, time.UTC), Status: entities.CarrierJumpStatusCompleted},
+	}
+
+	jumps := fc.CompletedJumps()
+	if len(jumps) != len(expected) {
+		t.Fatalf("expected %d jumps, got %+v", len(expected), jumps)
+	}
+	for i := range expected {
+		if jumps[i].Origin != expected[i].Origin || jumps[i].Destination != expected[i].Destination || !jumps[i].DepartureAt.Equal(expected[i].DepartureAt) || jumps[i].Status != expected[i].Status {
+			t.Errorf("jump %d: expected %+v, got %+v", i, expected[i], jumps[i])
+		}
+	}
+}
+
+func TestCapiTimeRejectsGarbage(t *testing.T) {
+	var parsed CapiTime
+	if err := parsed.UnmarshalJSON([]byte(`"yesterday"`)); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,31 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+func IsOwner(user *entities.User, cr *entities.Carrier) bool {
+	return cr.OwnerID != nil && *cr.OwnerID == user.ID
+}
+
+// Whether the user (or the token acting on behalf of the user) may use the scope on the carrier.
+// Tokens are limited to the carriers their scopes cover and may grant access to carriers the user does not own.
+func HasAccess(user *entities.User, token *entities.ApiToken, cr *entities.Carrier, scope entities.ApiTokenScope) bool {
+	if token != nil && !token.CoversCarrier(scope, cr.ID) {
+		return false
+	}
+	if user.IsAdmin || IsOwner(user, cr) {
+		return true
+	}
+	return token != nil && token.GrantsCarrier(scope, cr.ID)
+}
+
+// Whether the user (or the token acting on behalf of the user) may read the carrier.
+func CanRead(user *entities.User, token *entities.ApiToken, cr *entities.Carrier) bool {
+	return HasAccess(user, token, cr, entities.ScopeCarrierRead)
+}
+
+// Whether the user (or the token acting on behalf of the user) may modify the carrier.
+func CanWrite(user *entities.User, token *entities.ApiToken, cr *entities.Carrier) bool {
+	return HasAccess(user, token, cr, entities.ScopeCarrierWrite)
+}
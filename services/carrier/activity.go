@@ -0,0 +1,86 @@
+package carrier
+
+import (
+	"fmt"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/services/outbound"
+)
+
+var log = logging.Logger{Package: "services/carrier"}
+
+// Returns a copy of the carrier that is not affected by later changes to the original.
+func Snapshot(cr *entities.Carrier) entities.Carrier {
+	snapshot := *cr
+	snapshot.Services = append([]entities.CarrierService{}, cr.Services...)
+	snapshot.LocationHistory = append([]string{}, cr.LocationHistory...)
+	return snapshot
+}
+
+// Records activity log entries for everything that changed between the two states of a carrier.
+// Failing to record activity is logged but never fails the surrounding request.
+func RecordChanges(before *entities.Carrier, after *entities.Carrier) {
+	activities := []entities.CarrierActivity{}
+
+	if before.CurrentLocation != after.CurrentLocation {
+		activities = append(activities, entities.CarrierActivity{
+			Type:    entities.CarrierActivityJump,
+			Summary: fmt.Sprintf("%s jumped to %s", after.Name, after.CurrentLocation),
+			Details: fmt.Sprintf("From %s to %s", before.CurrentLocation, after.CurrentLocation),
+		})
+	}
+
+	if before.DockingAccess != after.DockingAccess {
+		activities = append(activities, entities.CarrierActivity{
+			Type:    entities.CarrierActivityDockingAccess,
+			Summary: fmt.Sprintf("%s changed docking access to %s", after.Name, after.DockingAccess),
+			Details: fmt.Sprintf("Docking access changed from %s to %s", before.DockingAccess, after.DockingAccess),
+		})
+	}
+
+	for _, service := range after.Services {
+		if !before.HasService(service) {
+			activities = append(activities, entities.CarrierActivity{
+				Type:    entities.CarrierActivityService,
+				Summary: fmt.Sprintf("%s activated %s", after.Name, service.Label),
+			})
+		}
+	}
+
+	for _, service := range before.Services {
+		if !after.HasService(service) {
+			activities = append(activities, entities.CarrierActivity{
+				Type:    entities.CarrierActivityService,
+				Summary: fmt.Sprintf("%s deactivated %s", after.Name, service.Label),
+			})
+		}
+	}
+
+	recordActivities(after, activities)
+
+	// opted in carriers mirror their state to Inara and EDSM
+	outbound.CarrierChanged(before, after)
+}
+
+// Records that the pending jump of the carrier was cancelled.
+func RecordJumpCancelled(cr *entities.Carrier) {
+	recordActivities(cr, []entities.CarrierActivity{{
+		Type:    entities.CarrierActivityJumpCancelled,
+		Summary: fmt.Sprintf("%s cancelled its jump", cr.Name),
+	}})
+}
+
+func recordActivities(cr *entities.Carrier, activities []entities.CarrierActivity) {
+	if len(activities) == 0 {
+		return
+	}
+
+	for i := range activities {
+		activities[i].CarrierID = cr.ID
+	}
+
+	if res := db.DB.Create(&activities); res.Error != nil {
+		log.Println("Failed to record carrier activity:", res.Error)
+	}
+}
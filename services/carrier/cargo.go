@@ -0,0 +1,144 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Returns the cargo manifest of the carrier, largest entries first.
+func GetCargo(cr *entities.Carrier) ([]entities.CarrierCargo, *errors.RstError) {
+	cargo := []entities.CarrierCargo{}
+	if res := db.DB.Where("carrier_id = ?", cr.ID).Order("quantity DESC, commodity").Find(&cargo); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return cargo, nil
+}
+
+// Replaces the whole cargo manifest of the carrier, e.g. with the inventory reported by the connector.
+func ReplaceCargo(cr *entities.Carrier, entries []entities.CarrierCargo) ([]entities.CarrierCargo, *errors.RstError) {
+	cargo, err := normalizeCargo(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return saveCargo(cr, cargo)
+}
+
+// Applies the given entries to the existing manifest. Entries with a quantity of zero are removed.
+func UpdateCargo(cr *entities.Carrier, entries []entities.CarrierCargo) ([]entities.CarrierCargo, *errors.RstError) {
+	updates, err := normalizeCargo(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	cargo, err := GetCargo(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, update := range updates {
+		found := false
+		for i := range cargo {
+			if cargo[i].SameEntry(&update) {
+				cargo[i].Quantity = update.Quantity
+				found = true
+				break
+			}
+		}
+		if !found {
+			cargo = append(cargo, update)
+		}
+	}
+
+	return saveCargo(cr, cargo)
+}
+
+// Validates that the manifest and the manually set cargo usage fit into the carrier's cargo space.
+func CheckCargoCapacity(cr *entities.Carrier) *errors.RstError {
+	if cr.CargoUsed < 0 || cr.CargoUsed > cr.CargoSpace {
+		return ErrCargoExceedsCapacity
+	}
+
+	var total int64
+	if res := db.DB.Model(&entities.CarrierCargo{}).Where("carrier_id = ?", cr.ID).Select("COALESCE(SUM(quantity), 0)").Scan(&total); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	if total > int64(cr.CargoSpace) {
+		return ErrCargoExceedsCapacity
+	}
+	return nil
+}
+
+// Normalizes commodity names and merges duplicate entries.
+func normalizeCargo(entries []entities.CarrierCargo) ([]entities.CarrierCargo, *errors.RstError) {
+	cargo := []entities.CarrierCargo{}
+	for _, entry := range entries {
+		entry.Commodity = entities.NormalizeCommodity(entry.Commodity)
+		if entry.Commodity == "" || entry.Quantity < 0 {
+			return nil, ErrInvalidCargo
+		}
+
+		merged := false
+		for i := range cargo {
+			if cargo[i].SameEntry(&entry) {
+				cargo[i].Quantity += entry.Quantity
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			cargo = append(cargo, entities.CarrierCargo{
+				Commodity: entry.Commodity,
+				Quantity:  entry.Quantity,
+				Stolen:    entry.Stolen,
+				Mission:   entry.Mission,
+			})
+		}
+	}
+	return cargo, nil
+}
+
+// Stores the manifest and keeps CargoUsed of the carrier in sync with it.
+func saveCargo(cr *entities.Carrier, cargo []entities.CarrierCargo) ([]entities.CarrierCargo, *errors.RstError) {
+	stored := []entities.CarrierCargo{}
+	total := 0
+	for _, entry := range cargo {
+		if entry.Quantity == 0 {
+			continue
+		}
+		entry.ID = uuid.Nil
+		entry.Model = gorm.Model{}
+		entry.CarrierID = cr.ID
+		total += entry.Quantity
+		stored = append(stored, entry)
+	}
+
+	if total > cr.CargoSpace {
+		return nil, ErrCargoExceedsCapacity
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Unscoped().Where("carrier_id = ?", cr.ID).Delete(&entities.CarrierCargo{}); res.Error != nil {
+			return res.Error
+		}
+
+		if len(stored) > 0 {
+			if res := tx.Create(&stored); res.Error != nil {
+				return res.Error
+			}
+		}
+
+		cr.CargoUsed = total
+		return tx.Model(cr).Update("cargo_used", total).Error
+	})
+	if err != nil {
+		return nil, errors.NewDBErrorFromError(err)
+	}
+
+	return stored, nil
+}
@@ -21,11 +21,27 @@ var (
 	ErrInvalidCarrierId       = errors.New(1004, *ErrPackageCarrier, 400, "", "Invalid Carrier ID")
 	ErrInvalidCategory        = errors.New(1005, *ErrPackageCarrier, 400, "", "Invalid Category")
 	ErrInvalidCarrierServices = errors.New(1006, *ErrPackageCarrier, 400, "", "Invalid Carrier Services")
+	ErrInvalidCarrierJumpId   = errors.New(1007, *ErrPackageCarrier, 400, "", "Invalid Carrier Jump ID")
+	ErrInvalidCargo           = errors.New(1008, *ErrPackageCarrier, 400, "", "Invalid Cargo entry")
+	ErrCargoExceedsCapacity   = errors.New(1009, *ErrPackageCarrier, 400, "", "Cargo exceeds the carrier's cargo space")
+	ErrInvalidProposalId      = errors.New(1010, *ErrPackageCarrier, 400, "", "Invalid Jump Proposal ID")
+	ErrInvalidDepartureTime   = errors.New(1011, *ErrPackageCarrier, 400, "", "Departure time has to be in the future")
+	ErrInvalidVotingDeadline  = errors.New(1012, *ErrPackageCarrier, 400, "", "Invalid voting deadline")
+	ErrInvalidMarketData      = errors.New(1013, *ErrPackageCarrier, 400, "", "Invalid market data")
 
 	ErrCarrierNotFound        = errors.New(2001, *ErrPackageCarrier, 404, "", "Carrier not found")
 	ErrCarrierServiceNotFound = errors.New(2002, *ErrPackageCarrier, 404, "", "Carrier Service not found")
-
-	ErrCarrierAlreadyExists = errors.New(3001, *ErrPackageCarrier, 409, "", "Carrier with same name or callsign already exists")
+	ErrCarrierJumpNotFound    = errors.New(2003, *ErrPackageCarrier, 404, "", "Carrier Jump not found")
+	ErrProposalNotFound       = errors.New(2004, *ErrPackageCarrier, 404, "", "Jump Proposal not found")
+
+	ErrCarrierAlreadyExists    = errors.New(3001, *ErrPackageCarrier, 409, "", "Carrier with same name or callsign already exists")
+	ErrCarrierJumpNotScheduled = errors.New(3002, *ErrPackageCarrier, 409, "", "Carrier Jump is not scheduled anymore")
+	ErrProposalNotOpen         = errors.New(3003, *ErrPackageCarrier, 409, "", "Jump Proposal is not open anymore")
+	ErrVotingClosed            = errors.New(3004, *ErrPackageCarrier, 409, "", "Voting for this Jump Proposal has ended")
+	ErrProposalNotWinning      = errors.New(3005, *ErrPackageCarrier, 409, "", "Only the proposal with the most votes can be approved")
+	ErrVotingStillOpen         = errors.New(3006, *ErrPackageCarrier, 409, "", "Voting for this Jump Proposal has not ended yet")
+	ErrOtherVotingStillOpen    = errors.New(3007, *ErrPackageCarrier, 409, "", "Voting for other open Jump Proposals has not ended yet")
+	ErrProposalTied            = errors.New(3008, *ErrPackageCarrier, 409, "", "Jump Proposal is tied with another one, reject the others to approve it")
 
 	ErrForbidden    = errors.New(4000, *ErrPackageCarrier, 403, "", "Forbidden")
 	ErrUnauthorized = errors.New(4001, *ErrPackageCarrier, 401, "", "Unauthorized")
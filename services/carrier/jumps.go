@@ -0,0 +1,117 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"strings"
+	"time"
+)
+
+// Returns where the carrier will be at the given time according to its scheduled jumps.
+func LocationAt(cr *entities.Carrier, at time.Time) string {
+	jump := entities.CarrierJump{}
+	res := db.DB.Where("carrier_id = ? AND status IN ? AND departure_at <= ?", cr.ID, []entities.CarrierJumpStatus{entities.CarrierJumpStatusPending, entities.CarrierJumpStatusPlanned}, at).Order("departure_at DESC").Limit(1).Find(&jump)
+	if res.Error != nil || res.RowsAffected == 0 {
+		return cr.CurrentLocation
+	}
+
+	return jump.Destination
+}
+
+// Records a jump plotted ingame. A planned jump to the same destination is turned into the pending jump,
+// previously pending jumps are considered completed as only one jump can be plotted at a time.
+func RecordPlottedJump(cr *entities.Carrier, origin string, destination string, departureAt time.Time) (*entities.CarrierJump, error) {
+	if res := db.DB.Model(&entities.CarrierJump{}).Where("carrier_id = ? AND status = ?", cr.ID, entities.CarrierJumpStatusPending).Update("status", entities.CarrierJumpStatusCompleted); res.Error != nil {
+		return nil, res.Error
+	}
+
+	jump := &entities.CarrierJump{}
+	res := db.DB.Where("carrier_id = ? AND status = ? AND LOWER(destination) = LOWER(?)", cr.ID, entities.CarrierJumpStatusPlanned, destination).Order("departure_at").Limit(1).Find(jump)
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		jump = &entities.CarrierJump{CarrierID: cr.ID}
+	}
+
+	jump.Origin = origin
+	jump.Destination = destination
+	jump.DepartureAt = departureAt
+	jump.Status = entities.CarrierJumpStatusPending
+
+	if res := db.DB.Save(jump); res.Error != nil {
+		return nil, res.Error
+	}
+
+	return jump, nil
+}
+
+// Records a jump that already happened, e.g. from the itinerary of the Companion API. A scheduled jump to the
+// destination that was due by then is completed instead of adding another one, jumps recorded before are skipped.
+func RecordCompletedJump(cr *entities.Carrier, origin string, destination string, departureAt time.Time) error {
+	res := db.DB.Where("carrier_id = ? AND status = ? AND LOWER(destination) = LOWER(?) AND departure_at = ?", cr.ID, entities.CarrierJumpStatusCompleted, destination, departureAt).Limit(1).Find(&entities.CarrierJump{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+
+	// scheduled departures are rarely met to the minute
+	jump := &entities.CarrierJump{}
+	res = db.DB.Where("carrier_id = ? AND status IN ? AND LOWER(destination) = LOWER(?) AND departure_at <= ?", cr.ID, []entities.CarrierJumpStatus{entities.CarrierJumpStatusPending, entities.CarrierJumpStatusPlanned}, destination, departureAt.Add(time.Hour)).Order("departure_at DESC").Limit(1).Find(jump)
+	if res.Error != nil {
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		jump = &entities.CarrierJump{CarrierID: cr.ID}
+	}
+
+	jump.Origin = origin
+	jump.Destination = destination
+	jump.DepartureAt = departureAt
+	jump.Status = entities.CarrierJumpStatusCompleted
+
+	return db.DB.Save(jump).Error
+}
+
+// Cancels the currently pending jump of the carrier, if any.
+func CancelPendingJump(cr *entities.Carrier) error {
+	return db.DB.Model(&entities.CarrierJump{}).Where("carrier_id = ? AND status = ?", cr.ID, entities.CarrierJumpStatusPending).Update("status", entities.CarrierJumpStatusCancelled).Error
+}
+
+// Updates the location of a carrier seen by a third party, e.g. on EDDN. If only the system is known and the carrier
+// is already stored somewhere in that system, the more precise stored location is kept.
+func RecordObservedLocation(cr *entities.Carrier, system string, location string) error {
+	if location == "" || strings.EqualFold(cr.CurrentLocation, location) {
+		return nil
+	}
+	if location == system && isInSystem(cr.CurrentLocation, system) {
+		return nil
+	}
+
+	before := Snapshot(cr)
+	cr.LocationHistory = append(cr.LocationHistory, cr.CurrentLocation)
+	cr.CurrentLocation = location
+
+	if res := db.DB.Save(cr); res.Error != nil {
+		return res.Error
+	}
+
+	// the carrier arrived, so a pending jump has been completed
+	if res := db.DB.Model(&entities.CarrierJump{}).Where("carrier_id = ? AND status = ?", cr.ID, entities.CarrierJumpStatusPending).Update("status", entities.CarrierJumpStatusCompleted); res.Error != nil {
+		return res.Error
+	}
+
+	RecordChanges(&before, cr)
+	return nil
+}
+
+// Whether the location (a system or a body name) lies within the system.
+func isInSystem(location string, system string) bool {
+	location = strings.ToLower(location)
+	system = strings.ToLower(system)
+	return location == system || strings.HasPrefix(location, system+" ")
+}
@@ -0,0 +1,201 @@
+package carrier
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Voting period used when the proposer does not set a deadline
+	defaultVotingPeriod = time.Hour * 24
+	maxVotingPeriod     = time.Hour * 24 * 14
+)
+
+// Files a new destination proposal for the carrier.
+func CreateProposal(cr *entities.Carrier, user *entities.User, destination string, departureAt *time.Time, note string, votingEndsAt *time.Time) (*entities.JumpProposal, *errors.RstError) {
+	now := time.Now()
+	if departureAt != nil && departureAt.Before(now) {
+		return nil, ErrInvalidDepartureTime
+	}
+
+	deadline := now.Add(defaultVotingPeriod)
+	if votingEndsAt != nil {
+		if votingEndsAt.Before(now) || votingEndsAt.After(now.Add(maxVotingPeriod)) {
+			return nil, ErrInvalidVotingDeadline
+		}
+		deadline = *votingEndsAt
+	}
+
+	proposal := &entities.JumpProposal{
+		CarrierID:    cr.ID,
+		ProposedByID: user.ID,
+		ProposedBy:   user,
+		Destination:  destination,
+		DepartureAt:  departureAt,
+		Note:         note,
+		VotingEndsAt: deadline,
+		Status:       entities.JumpProposalStatusOpen,
+		Votes:        []entities.JumpProposalVote{},
+	}
+
+	if res := db.DB.Omit("ProposedBy").Create(proposal); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return proposal, nil
+}
+
+// Returns the proposals of the carrier including their votes, newest first.
+func ListProposals(cr *entities.Carrier, status string) ([]entities.JumpProposal, *errors.RstError) {
+	query := db.DB.Where("carrier_id = ?", cr.ID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	proposals := []entities.JumpProposal{}
+	if res := query.Preload("Votes").Preload("ProposedBy").Order("created_at DESC").Limit(100).Find(&proposals); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return proposals, nil
+}
+
+func FindProposal(cr *entities.Carrier, id uuid.UUID) (*entities.JumpProposal, *errors.RstError) {
+	proposal := &entities.JumpProposal{}
+	if res := db.DB.Where("id = ? AND carrier_id = ?", id, cr.ID).Preload("Votes").Preload("ProposedBy").First(proposal); res.Error != nil {
+		if res.Error == gorm.ErrRecordNotFound {
+			return nil, ErrProposalNotFound
+		}
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return proposal, nil
+}
+
+// Votes for the proposal. A previous vote of the user for another open proposal of the same carrier is moved.
+func Vote(proposal *entities.JumpProposal, user *entities.User) *errors.RstError {
+	if !proposal.IsVotingOpen() {
+		return ErrVotingClosed
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		otherProposals := tx.Model(&entities.JumpProposal{}).Select("id").Where("carrier_id = ? AND status = ? AND id != ?", proposal.CarrierID, entities.JumpProposalStatusOpen, proposal.ID)
+		if res := tx.Unscoped().Where("user_id = ? AND proposal_id IN (?)", user.ID, otherProposals).Delete(&entities.JumpProposalVote{}); res.Error != nil {
+			return res.Error
+		}
+
+		vote := entities.JumpProposalVote{ProposalID: proposal.ID, UserID: user.ID}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&vote).Error
+	})
+	if err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+
+	return reloadVotes(proposal)
+}
+
+// Removes the vote of the user from the proposal.
+func RemoveVote(proposal *entities.JumpProposal, user *entities.User) *errors.RstError {
+	if !proposal.IsVotingOpen() {
+		return ErrVotingClosed
+	}
+
+	if res := db.DB.Unscoped().Where("proposal_id = ? AND user_id = ?", proposal.ID, user.ID).Delete(&entities.JumpProposalVote{}); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	return reloadVotes(proposal)
+}
+
+// Approves the winning proposal once the voting of all open proposals has ended and schedules the jump. All other open proposals of the carrier are rejected.
+// The departure defaults to the one suggested in the proposal.
+func ApproveProposal(cr *entities.Carrier, proposal *entities.JumpProposal, user *entities.User, departureAt *time.Time) (*entities.CarrierJump, *errors.RstError) {
+	if proposal.Status != entities.JumpProposalStatusOpen {
+		return nil, ErrProposalNotOpen
+	}
+	if proposal.IsVotingOpen() {
+		return nil, ErrVotingStillOpen
+	}
+
+	if departureAt == nil {
+		departureAt = proposal.DepartureAt
+	}
+	if departureAt == nil || departureAt.Before(time.Now()) {
+		return nil, ErrInvalidDepartureTime
+	}
+
+	openProposals, err := ListProposals(cr, string(entities.JumpProposalStatusOpen))
+	if err != nil {
+		return nil, err
+	}
+	// the result is only final once every open proposal stopped collecting votes. Ties are not broken here,
+	// the owner settles them by rejecting the other tied proposals first.
+	for _, other := range openProposals {
+		if other.ID == proposal.ID {
+			continue
+		}
+		if other.IsVotingOpen() {
+			return nil, ErrOtherVotingStillOpen
+		}
+		if len(other.Votes) > len(proposal.Votes) {
+			return nil, ErrProposalNotWinning
+		}
+		if len(other.Votes) == len(proposal.Votes) {
+			return nil, ErrProposalTied
+		}
+	}
+
+	jump := &entities.CarrierJump{
+		CarrierID:   cr.ID,
+		Origin:      LocationAt(cr, *departureAt),
+		Destination: proposal.Destination,
+		DepartureAt: *departureAt,
+		Note:        proposal.Note,
+		Status:      entities.CarrierJumpStatusPlanned,
+		CreatedByID: &user.ID,
+	}
+
+	txErr := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Create(jump); res.Error != nil {
+			return res.Error
+		}
+
+		if res := tx.Model(&entities.JumpProposal{}).Where("carrier_id = ? AND status = ? AND id != ?", cr.ID, entities.JumpProposalStatusOpen, proposal.ID).Update("status", entities.JumpProposalStatusRejected); res.Error != nil {
+			return res.Error
+		}
+
+		proposal.Status = entities.JumpProposalStatusApproved
+		proposal.JumpID = &jump.ID
+		return tx.Model(proposal).Updates(map[string]interface{}{"status": proposal.Status, "jump_id": proposal.JumpID}).Error
+	})
+	if txErr != nil {
+		return nil, errors.NewDBErrorFromError(txErr)
+	}
+
+	return jump, nil
+}
+
+// Closes the proposal without scheduling a jump, either rejected by the owner or withdrawn by the proposer.
+func CloseProposal(proposal *entities.JumpProposal, status entities.JumpProposalStatus) *errors.RstError {
+	if proposal.Status != entities.JumpProposalStatusOpen {
+		return ErrProposalNotOpen
+	}
+
+	proposal.Status = status
+	if res := db.DB.Model(proposal).Update("status", status); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	return nil
+}
+
+func reloadVotes(proposal *entities.JumpProposal) *errors.RstError {
+	votes := []entities.JumpProposalVote{}
+	if res := db.DB.Where("proposal_id = ?", proposal.ID).Find(&votes); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	proposal.Votes = votes
+	return nil
+}
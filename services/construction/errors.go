@@ -0,0 +1,33 @@
+package construction
+
+import "ruehrstaat-backend/errors"
+
+var ErrPackageConstruction = errors.NewPackage("Construction", "CS")
+
+// codes
+// 1xxx - invalid something
+// 2xxx - not found
+// 3xxx - already done / exists
+// 4xxx - forbidden
+// 5xxx - server error
+
+// 9xxx - other
+// 9999 - unknown error
+
+var (
+	ErrBadRequest          = errors.NewWithInternalMessage(1001, *ErrPackageConstruction, 400, "", "Bad Request", "In sentry there might be a more detailed error above")
+	ErrInvalidSiteId       = errors.New(1002, *ErrPackageConstruction, 400, "", "Invalid Construction Site ID")
+	ErrInvalidCarrierId    = errors.New(1003, *ErrPackageConstruction, 400, "", "Invalid Carrier ID")
+	ErrInvalidRequirement  = errors.New(1004, *ErrPackageConstruction, 400, "", "Invalid Construction Requirement")
+	ErrMissingSiteLocation = errors.New(1005, *ErrPackageConstruction, 400, "", "System and name are required for unknown construction sites")
+
+	ErrSiteNotFound    = errors.New(2001, *ErrPackageConstruction, 404, "", "Construction Site not found")
+	ErrCarrierNotFound = errors.New(2002, *ErrPackageConstruction, 404, "", "Carrier not found")
+
+	ErrSiteAlreadyExists = errors.New(3001, *ErrPackageConstruction, 409, "", "Construction Site with same market id already exists")
+
+	ErrForbidden    = errors.New(4000, *ErrPackageConstruction, 403, "", "Forbidden")
+	ErrUnauthorized = errors.New(4001, *ErrPackageConstruction, 401, "", "Unauthorized")
+
+	ErrInternalServerError = errors.NewWithInternalMessage(5001, *ErrPackageConstruction, 500, "", "Internal Server Error", "In sentry there might be a more detailed error above")
+)
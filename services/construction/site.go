@@ -0,0 +1,166 @@
+package construction
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A commodity requirement as reported by a depot or entered manually.
+type Resource struct {
+	Commodity      string
+	Label          string
+	RequiredAmount int
+	ProvidedAmount int
+}
+
+// State of a construction depot as reported by the ColonisationConstructionDepot journal event.
+// System and Name are only needed when the depot is not known yet.
+type DepotReport struct {
+	MarketID  string
+	System    string
+	Name      string
+	Progress  float64
+	Complete  bool
+	Failed    bool
+	Resources []Resource
+}
+
+// Loads the site including its requirements and linked carriers.
+func FindSite(id uuid.UUID) (*entities.ConstructionSite, *errors.RstError) {
+	site := &entities.ConstructionSite{}
+	res := db.DB.Where("id = ?", id).
+		Preload("Requirements", func(tx *gorm.DB) *gorm.DB { return tx.Order("commodity") }).
+		Preload("Carriers").
+		First(site)
+	if res.Error != nil {
+		if res.Error == gorm.ErrRecordNotFound {
+			return nil, ErrSiteNotFound
+		}
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return site, nil
+}
+
+// Updates (or creates) the site belonging to the reported depot.
+func ApplyDepotReport(report *DepotReport, user *entities.User) (*entities.ConstructionSite, *errors.RstError) {
+	if report.MarketID == "" || report.Progress < 0 || report.Progress > 1 {
+		return nil, ErrBadRequest
+	}
+
+	site := &entities.ConstructionSite{}
+	res := db.DB.Where("market_id = ?", report.MarketID).Limit(1).Find(site)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+
+	if res.RowsAffected == 0 {
+		if report.System == "" || report.Name == "" {
+			return nil, ErrMissingSiteLocation
+		}
+
+		// sites entered manually are matched by their location
+		res = db.DB.Where("market_id IS NULL AND LOWER(system) = LOWER(?) AND LOWER(name) = LOWER(?)", report.System, report.Name).Limit(1).Find(site)
+		if res.Error != nil {
+			return nil, errors.NewDBErrorFromError(res.Error)
+		}
+
+		if res.RowsAffected == 0 {
+			site = &entities.ConstructionSite{
+				System:      report.System,
+				Name:        report.Name,
+				CreatedByID: &user.ID,
+			}
+		}
+
+		marketID := report.MarketID
+		site.MarketID = &marketID
+	}
+
+	site.Progress = report.Progress
+	site.IsComplete = report.Complete
+	site.IsFailed = report.Failed
+
+	if res := db.DB.Save(site); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+
+	if err := SetRequirements(site, report.Resources); err != nil {
+		return nil, err
+	}
+
+	return FindSite(site.ID)
+}
+
+// Replaces the requirements of the site.
+func SetRequirements(site *entities.ConstructionSite, resources []Resource) *errors.RstError {
+	requirements := []entities.ConstructionRequirement{}
+	for _, resource := range resources {
+		commodity := entities.NormalizeCommodity(resource.Commodity)
+		if commodity == "" || resource.RequiredAmount < 0 || resource.ProvidedAmount < 0 {
+			return ErrInvalidRequirement
+		}
+
+		label := strings.TrimSpace(resource.Label)
+		if label == "" {
+			label = commodity
+		}
+
+		merged := false
+		for i := range requirements {
+			if requirements[i].Commodity == commodity {
+				requirements[i].RequiredAmount += resource.RequiredAmount
+				requirements[i].ProvidedAmount += resource.ProvidedAmount
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			requirements = append(requirements, entities.ConstructionRequirement{
+				SiteID:         site.ID,
+				Commodity:      commodity,
+				Label:          label,
+				RequiredAmount: resource.RequiredAmount,
+				ProvidedAmount: resource.ProvidedAmount,
+			})
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if res := tx.Unscoped().Where("site_id = ?", site.ID).Delete(&entities.ConstructionRequirement{}); res.Error != nil {
+			return res.Error
+		}
+
+		if len(requirements) > 0 {
+			if res := tx.Create(&requirements); res.Error != nil {
+				return res.Error
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+
+	site.Requirements = requirements
+	return nil
+}
+
+// Links a carrier to the site so its cargo is taken into account for the progress summary.
+func LinkCarrier(site *entities.ConstructionSite, cr *entities.Carrier) *errors.RstError {
+	if err := db.DB.Model(site).Association("Carriers").Append(cr); err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+	return nil
+}
+
+func UnlinkCarrier(site *entities.ConstructionSite, cr *entities.Carrier) *errors.RstError {
+	if err := db.DB.Model(site).Association("Carriers").Delete(cr); err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package construction
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"github.com/google/uuid"
+)
+
+// Amount of a commodity held by one of the linked carriers.
+type CarrierStock struct {
+	Carrier  entities.Carrier
+	Quantity int
+}
+
+type CommodityProgress struct {
+	Commodity string
+	Label     string
+	Required  int
+	Provided  int
+	Remaining int
+	// Amount already loaded on linked carriers
+	OnCarriers int
+	Carriers   []CarrierStock
+}
+
+// Progress of a construction site, including what the linked carriers already hold.
+type Summary struct {
+	Site      *entities.ConstructionSite
+	Required  int
+	Provided  int
+	Remaining int
+	// Part of the remaining amount that is already loaded on linked carriers
+	OnCarriers  int
+	Commodities []CommodityProgress
+}
+
+// Whether the cargo on the linked carriers covers everything that still has to be delivered.
+func (s *Summary) IsCovered() bool {
+	return s.OnCarriers >= s.Remaining
+}
+
+// Builds the progress summary of the site. The site needs its requirements and carriers loaded, see FindSite.
+func Summarize(site *entities.ConstructionSite) (*Summary, *errors.RstError) {
+	summary := &Summary{Site: site, Commodities: []CommodityProgress{}}
+
+	carriers := map[uuid.UUID]entities.Carrier{}
+	carrierIds := []uuid.UUID{}
+	for _, cr := range site.Carriers {
+		carriers[cr.ID] = cr
+		carrierIds = append(carrierIds, cr.ID)
+	}
+
+	commodities := []string{}
+	for _, requirement := range site.Requirements {
+		commodities = append(commodities, requirement.Commodity)
+	}
+
+	// stolen and mission cargo can not be delivered to a depot
+	cargo := []entities.CarrierCargo{}
+	if len(carrierIds) > 0 && len(commodities) > 0 {
+		res := db.DB.Where("carrier_id IN ? AND commodity IN ? AND stolen = ? AND mission = ? AND quantity > 0", carrierIds, commodities, false, false).
+			Order("quantity DESC").
+			Find(&cargo)
+		if res.Error != nil {
+			return nil, errors.NewDBErrorFromError(res.Error)
+		}
+	}
+
+	for _, requirement := range site.Requirements {
+		progress := CommodityProgress{
+			Commodity: requirement.Commodity,
+			Label:     requirement.Label,
+			Required:  requirement.RequiredAmount,
+			Provided:  requirement.ProvidedAmount,
+			Remaining: requirement.Remaining(),
+			Carriers:  []CarrierStock{},
+		}
+
+		for _, entry := range cargo {
+			if entry.Commodity != requirement.Commodity {
+				continue
+			}
+			progress.OnCarriers += entry.Quantity
+			progress.Carriers = append(progress.Carriers, CarrierStock{Carrier: carriers[entry.CarrierID], Quantity: entry.Quantity})
+		}
+
+		summary.Required += progress.Required
+		summary.Provided += progress.Provided
+		summary.Remaining += progress.Remaining
+		summary.OnCarriers += min(progress.OnCarriers, progress.Remaining)
+		summary.Commodities = append(summary.Commodities, progress)
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,80 @@
+package eddn
+
+import (
+	"errors"
+	"ruehrstaat-backend/constants"
+	"strings"
+	"time"
+)
+
+// Message of the commodity/3 schema
+type CommodityMessage struct {
+	SystemName           string      `json:"systemName"`
+	StationName          string      `json:"stationName"`
+	StationType          string      `json:"stationType,omitempty"`
+	CarrierDockingAccess string      `json:"carrierDockingAccess,omitempty"`
+	MarketID             int64       `json:"marketId"`
+	Horizons             bool        `json:"horizons"`
+	Odyssey              bool        `json:"odyssey"`
+	Timestamp            time.Time   `json:"timestamp"`
+	Commodities          []Commodity `json:"commodities"`
+}
+
+type Commodity struct {
+	Name          string `json:"name"`
+	MeanPrice     int    `json:"meanPrice"`
+	BuyPrice      int    `json:"buyPrice"`
+	Stock         int    `json:"stock"`
+	StockBracket  int    `json:"stockBracket"`
+	SellPrice     int    `json:"sellPrice"`
+	Demand        int    `json:"demand"`
+	DemandBracket int    `json:"demandBracket"`
+}
+
+// Checks the message against the constraints of the commodity/3 schema before it is sent.
+func (m *CommodityMessage) Validate() error {
+	if m.SystemName == "" || m.StationName == "" {
+		return errors.New("systemName and stationName are required")
+	}
+	if m.MarketID <= 0 {
+		return errors.New("marketId is required")
+	}
+	if m.Timestamp.IsZero() {
+		return errors.New("timestamp is required")
+	}
+	// the gateway rejects data that is too old or from the future
+	if m.Timestamp.After(time.Now().Add(time.Minute * 5)) {
+		return errors.New("timestamp lies in the future")
+	}
+
+	for _, commodity := range m.Commodities {
+		if commodity.Name == "" || strings.ContainsAny(commodity.Name, "$;") {
+			return errors.New("commodity name has to be the symbolic name without journal decoration")
+		}
+		if commodity.MeanPrice < 0 || commodity.BuyPrice < 0 || commodity.SellPrice < 0 || commodity.Stock < 0 || commodity.Demand < 0 {
+			return errors.New("prices, stock and demand can not be negative")
+		}
+		if commodity.StockBracket < 0 || commodity.StockBracket > 3 || commodity.DemandBracket < 0 || commodity.DemandBracket > 3 {
+			return errors.New("brackets have to be between 0 and 3")
+		}
+	}
+	return nil
+}
+
+// Wraps the message into an envelope uploaded on behalf of the commander.
+func NewCommodityEnvelope(uploaderID string, message *CommodityMessage, test bool) *OutgoingEnvelope {
+	schema := SchemaCommodity
+	if test {
+		schema += "/test"
+	}
+
+	return &OutgoingEnvelope{
+		SchemaRef: schema,
+		Header: Header{
+			UploaderID:      uploaderID,
+			SoftwareName:    constants.APP_NAME,
+			SoftwareVersion: constants.APP_VERSION,
+		},
+		Message: message,
+	}
+}
@@ -0,0 +1,193 @@
+package eddn
+
+import (
+	"context"
+	"io"
+	"os"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/services/carrier"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var log = logging.Logger{Package: "services/eddn"}
+
+const (
+	defaultRelayEndpoint = "tcp://eddn.edcd.io:9500"
+	// How often the known carriers are reloaded from the database
+	carrierIndexRefresh = time.Minute * 5
+	// Delay before reconnecting after the relay failed
+	reconnectDelay = time.Second * 30
+)
+
+var (
+	cancelListener context.CancelFunc
+	stopped        sync.WaitGroup
+)
+
+// Starts listening in the background. Reads EDDN_REPLAY_FILE instead of the live relay if set.
+func Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelListener = cancel
+
+	stopped.Add(1)
+	go func() {
+		defer stopped.Done()
+
+		if path := os.Getenv("EDDN_REPLAY_FILE"); path != "" {
+			subscriber, err := NewFileSubscriber(path)
+			if err != nil {
+				log.Println("Could not open EDDN replay file", err)
+				return
+			}
+			Listen(ctx, subscriber)
+			return
+		}
+
+		endpoint := os.Getenv("EDDN_RELAY")
+		if endpoint == "" {
+			endpoint = defaultRelayEndpoint
+		}
+
+		for ctx.Err() == nil {
+			subscriber, err := NewRelaySubscriber(ctx, endpoint)
+			if err != nil {
+				log.Println("Could not connect to EDDN relay", err)
+			} else {
+				log.Println("Listening to EDDN relay at", endpoint)
+				Listen(ctx, subscriber)
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(reconnectDelay):
+			}
+		}
+	}()
+}
+
+// Stops the listener started with Start and waits for it to finish.
+func Stop() {
+	if cancelListener == nil {
+		return
+	}
+	cancelListener()
+	stopped.Wait()
+}
+
+// Processes messages of the subscriber until it is exhausted, fails or the context is cancelled.
+// The subscriber is closed afterwards.
+func Listen(ctx context.Context, subscriber Subscriber) {
+	done := make(chan struct{})
+	defer close(done)
+	defer subscriber.Close()
+
+	// unblocks Receive when the listener gets stopped
+	go func() {
+		select {
+		case <-ctx.Done():
+			subscriber.Close()
+		case <-done:
+		}
+	}()
+
+	index := &carrierIndex{}
+	for ctx.Err() == nil {
+		raw, err := subscriber.Receive()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Println("Could not receive EDDN message", err)
+			}
+			return
+		}
+
+		if err := index.refreshIfStale(); err != nil {
+			log.Println("Could not load carriers", err)
+			continue
+		}
+
+		envelope, err := Decode(raw)
+		if err != nil {
+			continue
+		}
+
+		process(envelope, index)
+	}
+}
+
+// Updates the location of every known carrier sighted in the message.
+func process(envelope *Envelope, index *carrierIndex) {
+	if envelope.IsTest() {
+		return
+	}
+
+	sightings, err := Sightings(envelope)
+	if err != nil {
+		return
+	}
+
+	for _, sighting := range sightings {
+		carrierID, known := index.lookup(&sighting)
+		if !known {
+			continue
+		}
+
+		cr := entities.Carrier{}
+		if res := db.DB.Where("id = ?", carrierID).First(&cr); res.Error != nil {
+			continue
+		}
+
+		if err := carrier.RecordObservedLocation(&cr, sighting.System, sighting.Location()); err != nil {
+			log.Println("Could not update location of carrier", cr.ID, err)
+		}
+	}
+}
+
+// In-memory lookup of our carriers, so the database is only hit for relevant messages.
+type carrierIndex struct {
+	byMarketID map[string]uuid.UUID
+	byCallsign map[string]uuid.UUID
+	loadedAt   time.Time
+}
+
+func (i *carrierIndex) refreshIfStale() error {
+	if time.Since(i.loadedAt) < carrierIndexRefresh {
+		return nil
+	}
+
+	carriers := []entities.Carrier{}
+	if res := db.DB.Select("id", "market_id", "callsign").Find(&carriers); res.Error != nil {
+		return res.Error
+	}
+
+	i.byMarketID = map[string]uuid.UUID{}
+	i.byCallsign = map[string]uuid.UUID{}
+	for _, cr := range carriers {
+		i.byMarketID[cr.MarketID] = cr.ID
+		i.byCallsign[strings.ToUpper(cr.Callsign)] = cr.ID
+	}
+	i.loadedAt = time.Now()
+	return nil
+}
+
+func (i *carrierIndex) lookup(sighting *Sighting) (uuid.UUID, bool) {
+	if sighting.MarketID != "" {
+		if id, ok := i.byMarketID[sighting.MarketID]; ok {
+			return id, true
+		}
+	}
+	if sighting.Callsign != "" {
+		if id, ok := i.byCallsign[strings.ToUpper(sighting.Callsign)]; ok {
+			return id, true
+		}
+	}
+	return uuid.Nil, false
+}
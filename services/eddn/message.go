@@ -0,0 +1,96 @@
+package eddn
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	SchemaJournal             = "https://eddn.edcd.io/schemas/journal/1"
+	SchemaCommodity           = "https://eddn.edcd.io/schemas/commodity/3"
+	SchemaFSSSignalDiscovered = "https://eddn.edcd.io/schemas/fsssignaldiscovered/1"
+
+	// Real messages stay far below this, anything larger is most likely a zlib bomb
+	maxMessageSize = 4 * 1024 * 1024
+)
+
+var ErrMessageTooLarge = errors.New("eddn message exceeds the maximum size")
+
+type Envelope struct {
+	SchemaRef string          `json:"$schemaRef"`
+	Header    Header          `json:"header"`
+	Message   json.RawMessage `json:"message"`
+}
+
+type Header struct {
+	UploaderID       string `json:"uploaderID"`
+	SoftwareName     string `json:"softwareName"`
+	SoftwareVersion  string `json:"softwareVersion"`
+	GatewayTimestamp string `json:"gatewayTimestamp,omitempty"`
+}
+
+// Relevant fields of the Docked, Location and CarrierJump journal events
+type journalMessage struct {
+	Event       string `json:"event"`
+	StarSystem  string `json:"StarSystem"`
+	Body        string `json:"Body"`
+	StationName string `json:"StationName"`
+	StationType string `json:"StationType"`
+	MarketID    int64  `json:"MarketID"`
+	Docked      bool   `json:"Docked"`
+}
+
+type commodityMessage struct {
+	SystemName  string `json:"systemName"`
+	StationName string `json:"stationName"`
+	MarketID    int64  `json:"marketId"`
+}
+
+type fssSignalDiscoveredMessage struct {
+	StarSystem string `json:"StarSystem"`
+	Signals    []struct {
+		SignalName string `json:"SignalName"`
+		IsStation  bool   `json:"IsStation"`
+	} `json:"signals"`
+}
+
+// Decodes a raw message, decompressing it first if needed. Messages larger than maxMessageSize are rejected.
+func Decode(raw []byte) (*Envelope, error) {
+	data := raw
+	if len(raw) > 0 && raw[0] != '{' {
+		reader, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		// one byte more than allowed tells oversized messages apart from ones of exactly the maximum size
+		data, err = io.ReadAll(io.LimitReader(reader, maxMessageSize+1))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(data) > maxMessageSize {
+		return nil, ErrMessageTooLarge
+	}
+
+	envelope := &Envelope{}
+	if err := json.Unmarshal(data, envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// Schema without the "/test" suffix used by test messages.
+func (e *Envelope) Schema() string {
+	return strings.TrimSuffix(e.SchemaRef, "/test")
+}
+
+func (e *Envelope) IsTest() bool {
+	return strings.HasSuffix(e.SchemaRef, "/test")
+}
@@ -0,0 +1,162 @@
+package eddn
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultUploadEndpoint = "https://eddn.edcd.io:4430/upload/"
+	defaultUploadWorkers  = 2
+	defaultUploadQueue    = 100
+)
+
+var ErrUploadQueueFull = errors.New("eddn upload queue is full")
+
+type OutgoingEnvelope struct {
+	SchemaRef string      `json:"$schemaRef"`
+	Header    Header      `json:"header"`
+	Message   interface{} `json:"message"`
+}
+
+// Uploads messages to an EDDN gateway.
+type Publisher struct {
+	Endpoint string
+	Client   *http.Client
+	// Number of attempts for messages failing with server or network errors
+	MaxAttempts int
+	// Delay before the first retry, doubled for every further retry
+	RetryDelay time.Duration
+	// Whether messages are sent with the "/test" schemas
+	Test bool
+	// Uploads running in the background at once and messages waiting for them, see Enqueue
+	Workers   int
+	QueueSize int
+
+	queue     chan *OutgoingEnvelope
+	startOnce sync.Once
+}
+
+// Publisher configured via EDDN_UPLOAD_URL and EDDN_UPLOAD_TEST, e.g. pointing to a local stand-in of the gateway.
+func NewPublisher() *Publisher {
+	endpoint := os.Getenv("EDDN_UPLOAD_URL")
+	if endpoint == "" {
+		endpoint = defaultUploadEndpoint
+	}
+
+	return &Publisher{
+		Endpoint:    endpoint,
+		Client:      &http.Client{Timeout: time.Second * 10},
+		MaxAttempts: 3,
+		RetryDelay:  time.Second * 2,
+		Test:        os.Getenv("EDDN_UPLOAD_TEST") == "true",
+		Workers:     defaultUploadWorkers,
+		QueueSize:   defaultUploadQueue,
+	}
+}
+
+var (
+	defaultPublisher     *Publisher
+	defaultPublisherOnce sync.Once
+)
+
+// Shared publisher, created from the environment on first use.
+func DefaultPublisher() *Publisher {
+	defaultPublisherOnce.Do(func() {
+		defaultPublisher = NewPublisher()
+	})
+	return defaultPublisher
+}
+
+// Validates and uploads commodity/3 market data.
+func (p *Publisher) PublishCommodity(uploaderID string, message *CommodityMessage) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+	return p.Publish(NewCommodityEnvelope(uploaderID, message, p.Test))
+}
+
+// Validates the market data and queues it for the background uploads, so callers do not wait for retries.
+// Fails with ErrUploadQueueFull instead of piling up uploads while the gateway is slow.
+func (p *Publisher) EnqueueCommodity(uploaderID string, message *CommodityMessage) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+	return p.Enqueue(NewCommodityEnvelope(uploaderID, message, p.Test))
+}
+
+func (p *Publisher) Enqueue(envelope *OutgoingEnvelope) error {
+	p.startOnce.Do(p.startWorkers)
+
+	select {
+	case p.queue <- envelope:
+		return nil
+	default:
+		return ErrUploadQueueFull
+	}
+}
+
+func (p *Publisher) startWorkers() {
+	p.queue = make(chan *OutgoingEnvelope, max(p.QueueSize, 1))
+	for i := 0; i < max(p.Workers, 1); i++ {
+		go func() {
+			for envelope := range p.queue {
+				if err := p.Publish(envelope); err != nil {
+					log.Println("Could not publish", envelope.SchemaRef, "to EDDN:", err)
+				}
+			}
+		}()
+	}
+}
+
+// Uploads the envelope, retrying server and network errors. Rejected messages are not retried.
+func (p *Publisher) Publish(envelope *OutgoingEnvelope) error {
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	delay := p.RetryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := p.upload(body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= p.MaxAttempts {
+			return err
+		}
+
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// Sends the message once, reports whether a failure is worth retrying.
+func (p *Publisher) upload(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, p.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusOK {
+		return false, nil
+	}
+
+	// the gateway explains rejected messages in the body
+	detail, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests, fmt.Errorf("EDDN gateway responded with %d: %s", res.StatusCode, bytes.TrimSpace(detail))
+}
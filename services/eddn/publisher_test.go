@@ -0,0 +1,160 @@
+package eddn
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"ruehrstaat-backend/constants"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPublisher(endpoint string) *Publisher {
+	return &Publisher{
+		Endpoint:    endpoint,
+		Client:      &http.Client{Timeout: time.Second * 5},
+		MaxAttempts: 3,
+		RetryDelay:  time.Millisecond,
+		Test:        true,
+		Workers:     1,
+		QueueSize:   1,
+	}
+}
+
+func testCommodityMessage() *CommodityMessage {
+	return &CommodityMessage{
+		SystemName:  "Sol",
+		StationName: "K7Q-BQL",
+		StationType: "FleetCarrier",
+		MarketID:    3700005632,
+		Horizons:    true,
+		Odyssey:     true,
+		Timestamp:   time.Now().UTC().Add(-time.Minute),
+		Commodities: []Commodity{{Name: "tritium", MeanPrice: 51707, SellPrice: 48000, Demand: 2000, DemandBracket: 3}},
+	}
+}
+
+func TestPublishCommodity(t *testing.T) {
+	var received struct {
+		SchemaRef string           `json:"$schemaRef"`
+		Header    Header           `json:"header"`
+		Message   CommodityMessage `json:"message"`
+	}
+	var contentType string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid body %s: %v", body, err)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := testPublisher(server.URL).PublishCommodity("CMDR Tester", testCommodityMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("expected json content type, got %q", contentType)
+	}
+	if received.SchemaRef != SchemaCommodity+"/test" {
+		t.Errorf("expected commodity/3 test schema, got %q", received.SchemaRef)
+	}
+	if received.Header.UploaderID != "CMDR Tester" || received.Header.SoftwareName != constants.APP_NAME || received.Header.SoftwareVersion != constants.APP_VERSION {
+		t.Errorf("unexpected header %+v", received.Header)
+	}
+	if received.Message.MarketID != 3700005632 || len(received.Message.Commodities) != 1 || received.Message.Commodities[0].Name != "tritium" {
+		t.Errorf("unexpected message %+v", received.Message)
+	}
+}
+
+func TestPublishRetriesServerErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if err := testPublisher(server.URL).PublishCommodity("CMDR Tester", testCommodityMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestPublishGivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	if err := testPublisher(server.URL).PublishCommodity("CMDR Tester", testCommodityMessage()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts.Load())
+	}
+}
+
+func TestPublishDoesNotRetryRejectedMessages(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("FAIL: schema validation failed"))
+	}))
+	defer server.Close()
+
+	if err := testPublisher(server.URL).PublishCommodity("CMDR Tester", testCommodityMessage()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts.Load() != 1 {
+		t.Fatalf("expected a single attempt, got %d", attempts.Load())
+	}
+}
+
+func TestEnqueueIsBounded(t *testing.T) {
+	release := make(chan struct{})
+	var uploads sync.WaitGroup
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		<-release
+		w.WriteHeader(http.StatusOK)
+		uploads.Done()
+	}))
+	defer server.Close()
+
+	publisher := testPublisher(server.URL)
+
+	// the only worker picks up the first message and blocks, the second one waits in the queue
+	uploads.Add(2)
+	if err := publisher.EnqueueCommodity("CMDR Tester", testCommodityMessage()); err != nil {
+		t.Fatal(err)
+	}
+	for attempts.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := publisher.EnqueueCommodity("CMDR Tester", testCommodityMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := publisher.EnqueueCommodity("CMDR Tester", testCommodityMessage()); err != ErrUploadQueueFull {
+		t.Fatalf("expected ErrUploadQueueFull, got %v", err)
+	}
+
+	close(release)
+	uploads.Wait()
+}
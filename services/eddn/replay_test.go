@@ -0,0 +1,137 @@
+package eddn
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type matchedSighting struct {
+	carrier  uuid.UUID
+	system   string
+	location string
+}
+
+// Replays the recorded fixture against an index of our carriers, the way Listen does without touching the database.
+func replayFixture(t *testing.T, index *carrierIndex) []matchedSighting {
+	t.Helper()
+
+	subscriber, err := NewFileSubscriber("testdata/replay.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscriber.Close()
+
+	matched := []matchedSighting{}
+	for {
+		raw, err := subscriber.Receive()
+		if err == io.EOF {
+			return matched
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		envelope, err := Decode(raw)
+		if err != nil {
+			t.Fatalf("could not decode %s: %v", raw, err)
+		}
+		if envelope.IsTest() {
+			continue
+		}
+
+		sightings, err := Sightings(envelope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, sighting := range sightings {
+			if id, known := index.lookup(&sighting); known {
+				matched = append(matched, matchedSighting{carrier: id, system: sighting.System, location: sighting.Location()})
+			}
+		}
+	}
+}
+
+func TestReplayMatchesKnownCarriers(t *testing.T) {
+	// matched by market id, the callsign in the index is outdated on purpose
+	byMarket := uuid.New()
+	// matched by callsign from a commodity message with a market id we do not know
+	byCallsign := uuid.New()
+	// matched by the callsign at the end of the FSSSignalDiscovered signal name
+	bySignal := uuid.New()
+
+	index := &carrierIndex{
+		byMarketID: map[string]uuid.UUID{"3700005632": byMarket},
+		byCallsign: map[string]uuid.UUID{
+			"OLD-001": byMarket,
+			"X9T-22Z": byCallsign,
+			"Q2V-1NK": bySignal,
+		},
+		loadedAt: time.Now(),
+	}
+
+	expected := []matchedSighting{
+		{carrier: byMarket, system: "Sol", location: "Sol"},
+		{carrier: byCallsign, system: "Colonia", location: "Colonia"},
+		{carrier: bySignal, system: "Deciat", location: "Deciat"},
+		{carrier: byMarket, system: "Sagittarius A*", location: "Sagittarius A* A 1"},
+	}
+
+	matched := replayFixture(t, index)
+	if len(matched) != len(expected) {
+		t.Fatalf("expected %d matched sightings, got %d: %+v", len(expected), len(matched), matched)
+	}
+	for i := range expected {
+		if matched[i] != expected[i] {
+			t.Errorf("sighting %d: expected %+v, got %+v", i, expected[i], matched[i])
+		}
+	}
+}
+
+func TestReplayIgnoresUnknownCarriers(t *testing.T) {
+	index := &carrierIndex{byMarketID: map[string]uuid.UUID{}, byCallsign: map[string]uuid.UUID{}, loadedAt: time.Now()}
+
+	if matched := replayFixture(t, index); len(matched) != 0 {
+		t.Fatalf("expected no matches, got %+v", matched)
+	}
+}
+
+func compress(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	buffer := &bytes.Buffer{}
+	writer := zlib.NewWriter(buffer)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buffer.Bytes()
+}
+
+func TestDecodeCompressed(t *testing.T) {
+	raw := compress(t, []byte(`{"$schemaRef":"https://eddn.edcd.io/schemas/journal/1/test","header":{"uploaderID":"cmdr"},"message":{}}`))
+
+	envelope, err := Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envelope.Schema() != SchemaJournal || !envelope.IsTest() {
+		t.Fatalf("unexpected schema %q", envelope.SchemaRef)
+	}
+}
+
+func TestDecodeRejectsOversizedMessages(t *testing.T) {
+	// compresses to a few kilobytes but inflates beyond the limit
+	padding := bytes.Repeat([]byte(" "), maxMessageSize)
+	raw := compress(t, append([]byte(`{"$schemaRef":"https://eddn.edcd.io/schemas/journal/1","message":{}}`), padding...))
+
+	if _, err := Decode(raw); err != ErrMessageTooLarge {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package eddn
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Fleet carrier callsigns look like "XXX-XXX" and are used as station name, signal names end with them
+var callsignPattern = regexp.MustCompile(`([A-Z0-9]{3}-[A-Z0-9]{3})$`)
+
+const stationTypeFleetCarrier = "FleetCarrier"
+
+// A fleet carrier seen by some commander. Either MarketID or Callsign may be empty.
+type Sighting struct {
+	MarketID string
+	Callsign string
+	System   string
+	// Only known for CarrierJump events
+	Body string
+}
+
+// Location to store for the carrier, the body if known and otherwise the system.
+func (s *Sighting) Location() string {
+	if s.Body != "" {
+		return s.Body
+	}
+	return s.System
+}
+
+// Extracts all fleet carrier sightings of a message. Unknown schemas yield none.
+func Sightings(envelope *Envelope) ([]Sighting, error) {
+	switch envelope.Schema() {
+	case SchemaJournal:
+		msg := journalMessage{}
+		if err := json.Unmarshal(envelope.Message, &msg); err != nil {
+			return nil, err
+		}
+		return journalSightings(&msg), nil
+	case SchemaCommodity:
+		msg := commodityMessage{}
+		if err := json.Unmarshal(envelope.Message, &msg); err != nil {
+			return nil, err
+		}
+		callsign := callsignPattern.FindString(msg.StationName)
+		if callsign == "" || msg.StationName != callsign || msg.SystemName == "" {
+			return nil, nil
+		}
+		return []Sighting{{MarketID: formatMarketID(msg.MarketID), Callsign: callsign, System: msg.SystemName}}, nil
+	case SchemaFSSSignalDiscovered:
+		msg := fssSignalDiscoveredMessage{}
+		if err := json.Unmarshal(envelope.Message, &msg); err != nil {
+			return nil, err
+		}
+		sightings := []Sighting{}
+		for _, signal := range msg.Signals {
+			callsign := callsignPattern.FindString(strings.TrimSpace(signal.SignalName))
+			if !signal.IsStation || callsign == "" || msg.StarSystem == "" {
+				continue
+			}
+			sightings = append(sightings, Sighting{Callsign: callsign, System: msg.StarSystem})
+		}
+		return sightings, nil
+	default:
+		return nil, nil
+	}
+}
+
+func journalSightings(msg *journalMessage) []Sighting {
+	if msg.StationType != stationTypeFleetCarrier || msg.StarSystem == "" {
+		return nil
+	}
+
+	sighting := Sighting{
+		MarketID: formatMarketID(msg.MarketID),
+		Callsign: callsignPattern.FindString(msg.StationName),
+		System:   msg.StarSystem,
+	}
+
+	switch msg.Event {
+	case "Docked":
+		return []Sighting{sighting}
+	case "Location":
+		if !msg.Docked {
+			return nil
+		}
+		return []Sighting{sighting}
+	case "CarrierJump":
+		if !msg.Docked {
+			return nil
+		}
+		sighting.Body = msg.Body
+		return []Sighting{sighting}
+	default:
+		return nil
+	}
+}
+
+func formatMarketID(marketID int64) string {
+	if marketID == 0 {
+		return ""
+	}
+	return strconv.FormatInt(marketID, 10)
+}
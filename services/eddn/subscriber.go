@@ -0,0 +1,88 @@
+package eddn
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+
+	"github.com/go-zeromq/zmq4"
+)
+
+// Source of raw EDDN messages. Messages are either zlib compressed (as sent by the relay) or plain JSON.
+type Subscriber interface {
+	// Blocks until the next message is available, returns io.EOF once no more messages will follow.
+	Receive() ([]byte, error)
+	Close() error
+}
+
+// Subscribes to the live EDDN ZeroMQ relay.
+type RelaySubscriber struct {
+	socket zmq4.Socket
+}
+
+func NewRelaySubscriber(ctx context.Context, endpoint string) (*RelaySubscriber, error) {
+	socket := zmq4.NewSub(ctx, zmq4.WithAutomaticReconnect(true))
+	if err := socket.Dial(endpoint); err != nil {
+		socket.Close()
+		return nil, err
+	}
+
+	// the relay publishes everything without topics
+	if err := socket.SetOption(zmq4.OptionSubscribe, ""); err != nil {
+		socket.Close()
+		return nil, err
+	}
+
+	return &RelaySubscriber{socket: socket}, nil
+}
+
+func (s *RelaySubscriber) Receive() ([]byte, error) {
+	msg, err := s.socket.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+func (s *RelaySubscriber) Close() error {
+	return s.socket.Close()
+}
+
+// Replays a recorded message file with one JSON message per line, e.g. to test the listener.
+type FileSubscriber struct {
+	file    *os.File
+	scanner *bufio.Scanner
+}
+
+func NewFileSubscriber(path string) (*FileSubscriber, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	scanner := bufio.NewScanner(file)
+	// journal messages may exceed the default line length
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	return &FileSubscriber{file: file, scanner: scanner}, nil
+}
+
+func (s *FileSubscriber) Receive() ([]byte, error) {
+	for s.scanner.Scan() {
+		line := s.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		return append([]byte{}, line...), nil
+	}
+
+	if err := s.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (s *FileSubscriber) Close() error {
+	return s.file.Close()
+}
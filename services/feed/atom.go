@@ -0,0 +1,75 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"feed"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  *AtomPerson `xml:"author,omitempty"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID       string        `xml:"id"`
+	Title    string        `xml:"title"`
+	Updated  string        `xml:"updated"`
+	Category *AtomCategory `xml:"category,omitempty"`
+	Summary  string        `xml:"summary,omitempty"`
+	Links    []AtomLink    `xml:"link,omitempty"`
+}
+
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Creates a new feed, the updated timestamp is the time of the newest entry.
+func NewAtomFeed(id string, title string, selfUrl string, updated time.Time) *AtomFeed {
+	return &AtomFeed{
+		Xmlns:   atomNamespace,
+		ID:      id,
+		Title:   title,
+		Updated: FormatTime(updated),
+		Author:  &AtomPerson{Name: "Ruehrstaat Squadron"},
+		Links: []AtomLink{
+			{Href: selfUrl, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: []AtomEntry{},
+	}
+}
+
+func (f *AtomFeed) AddEntry(entry AtomEntry) {
+	f.Entries = append(f.Entries, entry)
+}
+
+func (f *AtomFeed) Render() ([]byte, error) {
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// Formats a timestamp as RFC 3339 as required by Atom.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,33 @@
+package logistics
+
+import "ruehrstaat-backend/errors"
+
+var ErrPackageLogistics = errors.NewPackage("Logistics", "L")
+
+// codes
+// 1xxx - invalid something
+// 2xxx - not found
+// 3xxx - already done / exists
+// 4xxx - forbidden
+// 5xxx - server error
+
+// 9xxx - other
+// 9999 - unknown error
+
+var (
+	ErrBadRequest       = errors.NewWithInternalMessage(1001, *ErrPackageLogistics, 400, "", "Bad Request", "In sentry there might be a more detailed error above")
+	ErrInvalidRequestId = errors.New(1002, *ErrPackageLogistics, 400, "", "Invalid Logistics Request ID")
+	ErrInvalidCarrierId = errors.New(1003, *ErrPackageLogistics, 400, "", "Invalid Carrier ID")
+	ErrInvalidType      = errors.New(1004, *ErrPackageLogistics, 400, "", "Invalid Logistics Request type")
+	ErrInvalidCargo     = errors.New(1005, *ErrPackageLogistics, 400, "", "Haul requests need a commodity and a positive quantity")
+	ErrInvalidDeadline  = errors.New(1006, *ErrPackageLogistics, 400, "", "Deadline has to be in the future")
+
+	ErrRequestNotFound = errors.New(2001, *ErrPackageLogistics, 404, "", "Logistics Request not found")
+	ErrCarrierNotFound = errors.New(2002, *ErrPackageLogistics, 404, "", "Carrier not found")
+
+	ErrInvalidTransition = errors.New(3001, *ErrPackageLogistics, 409, "", "Logistics Request can not change to this status anymore")
+
+	ErrForbidden = errors.New(4000, *ErrPackageLogistics, 403, "", "Forbidden")
+
+	ErrInternalServerError = errors.NewWithInternalMessage(5001, *ErrPackageLogistics, 500, "", "Internal Server Error", "In sentry there might be a more detailed error above")
+)
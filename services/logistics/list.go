@@ -0,0 +1,40 @@
+package logistics
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+
+	"gorm.io/gorm"
+)
+
+// Requests filed by the user, newest first.
+func ListForUser(user *entities.User, status string) ([]entities.LogisticsRequest, *errors.RstError) {
+	return list(db.DB.Where("requester_id = ?", user.ID), status)
+}
+
+// Requests assigned to the carrier, newest first.
+func ListForCarrier(cr *entities.Carrier, status string) ([]entities.LogisticsRequest, *errors.RstError) {
+	return list(db.DB.Where("carrier_id = ?", cr.ID), status)
+}
+
+// Open requests the user could pick up: unassigned ones and those addressed to carriers the user manages.
+func ListOpen(user *entities.User) ([]entities.LogisticsRequest, *errors.RstError) {
+	query := db.DB.Where("status = ?", entities.LogisticsRequestStatusOpen)
+	if !user.IsAdmin {
+		query = query.Where("carrier_id IS NULL OR carrier_id IN (?)", db.DB.Model(&entities.Carrier{}).Select("id").Where("owner_id = ?", user.ID))
+	}
+	return list(query, "")
+}
+
+func list(query *gorm.DB, status string) ([]entities.LogisticsRequest, *errors.RstError) {
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	requests := []entities.LogisticsRequest{}
+	if res := query.Preload("Requester").Preload("Carrier").Order("created_at DESC").Limit(200).Find(&requests); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return requests, nil
+}
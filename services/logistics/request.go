@@ -0,0 +1,203 @@
+package logistics
+
+import (
+	"fmt"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/mailer"
+	"ruehrstaat-backend/mailer/mails"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var log = logging.Logger{Package: "services/logistics"}
+
+// Status changes a request may go through, the requester may additionally cancel any active request.
+var transitions = map[entities.LogisticsRequestStatus][]entities.LogisticsRequestStatus{
+	entities.LogisticsRequestStatusOpen:     {entities.LogisticsRequestStatusClaimed, entities.LogisticsRequestStatusAccepted, entities.LogisticsRequestStatusDeclined},
+	entities.LogisticsRequestStatusClaimed:  {entities.LogisticsRequestStatusAccepted, entities.LogisticsRequestStatusDeclined},
+	entities.LogisticsRequestStatusAccepted: {entities.LogisticsRequestStatusCompleted, entities.LogisticsRequestStatusDeclined},
+}
+
+// Validates and stores a new request filed by the requester.
+func Create(requester *entities.User, request *entities.LogisticsRequest) *errors.RstError {
+	switch request.Type {
+	case entities.LogisticsRequestTypeHaul:
+		request.Commodity = entities.NormalizeCommodity(request.Commodity)
+		if request.Commodity == "" || request.Quantity <= 0 {
+			return ErrInvalidCargo
+		}
+	case entities.LogisticsRequestTypeJump:
+		request.Commodity = ""
+		request.Quantity = 0
+	default:
+		return ErrInvalidType
+	}
+
+	if request.DestinationSystem == "" {
+		return ErrBadRequest
+	}
+
+	if request.Deadline != nil && request.Deadline.Before(time.Now()) {
+		return ErrInvalidDeadline
+	}
+
+	request.RequesterID = requester.ID
+	request.Requester = requester
+	request.Status = entities.LogisticsRequestStatusOpen
+
+	if res := db.DB.Create(request); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	return nil
+}
+
+// Loads the request including requester and carrier.
+func Find(id uuid.UUID) (*entities.LogisticsRequest, *errors.RstError) {
+	request := &entities.LogisticsRequest{}
+	if res := db.DB.Where("id = ?", id).Preload("Requester").Preload("Carrier").First(request); res.Error != nil {
+		if res.Error == gorm.ErrRecordNotFound {
+			return nil, ErrRequestNotFound
+		}
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return request, nil
+}
+
+// Whether the user manages the given carrier, i.e. is its owner or an admin.
+func IsCarrierManager(user *entities.User, cr *entities.Carrier) bool {
+	return user.IsAdmin || (cr != nil && cr.OwnerID != nil && *cr.OwnerID == user.ID)
+}
+
+// Whether the user may see the request. Open requests without a carrier are visible to every member.
+func CanView(user *entities.User, request *entities.LogisticsRequest) bool {
+	if request.RequesterID == user.ID || IsCarrierManager(user, request.Carrier) {
+		return true
+	}
+	return request.Status == entities.LogisticsRequestStatusOpen && request.CarrierID == nil
+}
+
+// Claims the request for one of the manager's carriers.
+func Claim(request *entities.LogisticsRequest, cr *entities.Carrier, manager *entities.User) *errors.RstError {
+	if !IsCarrierManager(manager, cr) {
+		return ErrForbidden
+	}
+	if request.Carrier != nil && request.Carrier.ID != cr.ID && !IsCarrierManager(manager, request.Carrier) {
+		return ErrForbidden
+	}
+
+	request.CarrierID = &cr.ID
+	request.Carrier = cr
+	return transition(request, entities.LogisticsRequestStatusClaimed, manager, "")
+}
+
+// Accepts the request. Requests without a carrier have to be claimed first.
+func Accept(request *entities.LogisticsRequest, manager *entities.User, response string) *errors.RstError {
+	return managerTransition(request, entities.LogisticsRequestStatusAccepted, manager, response)
+}
+
+func Decline(request *entities.LogisticsRequest, manager *entities.User, response string) *errors.RstError {
+	return managerTransition(request, entities.LogisticsRequestStatusDeclined, manager, response)
+}
+
+func Complete(request *entities.LogisticsRequest, manager *entities.User, response string) *errors.RstError {
+	return managerTransition(request, entities.LogisticsRequestStatusCompleted, manager, response)
+}
+
+// Cancels the request on behalf of the requester, no email is sent in that case.
+func Cancel(request *entities.LogisticsRequest, user *entities.User) *errors.RstError {
+	if request.RequesterID != user.ID && !user.IsAdmin {
+		return ErrForbidden
+	}
+	if !request.IsActive() {
+		return ErrInvalidTransition
+	}
+
+	request.Status = entities.LogisticsRequestStatusCancelled
+	if res := db.DB.Omit("Requester", "Carrier").Save(request); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	if request.RequesterID != user.ID {
+		notifyRequester(request)
+	}
+	return nil
+}
+
+func managerTransition(request *entities.LogisticsRequest, status entities.LogisticsRequestStatus, manager *entities.User, response string) *errors.RstError {
+	if request.Carrier == nil {
+		// unassigned requests may only be declined by admins, everyone else has to claim them first
+		if !manager.IsAdmin || status != entities.LogisticsRequestStatusDeclined {
+			return ErrInvalidTransition
+		}
+	} else if !IsCarrierManager(manager, request.Carrier) {
+		return ErrForbidden
+	}
+
+	return transition(request, status, manager, response)
+}
+
+func transition(request *entities.LogisticsRequest, status entities.LogisticsRequestStatus, manager *entities.User, response string) *errors.RstError {
+	allowed := false
+	for _, next := range transitions[request.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrInvalidTransition
+	}
+
+	request.Status = status
+	request.HandledByID = &manager.ID
+	request.Response = response
+
+	if res := db.DB.Omit("Requester", "Carrier").Save(request); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+
+	notifyRequester(request)
+	return nil
+}
+
+// Short human readable description of the request, e.g. "1200t tritium to Sol".
+func Describe(request *entities.LogisticsRequest) string {
+	if request.Type == entities.LogisticsRequestTypeJump {
+		return "Jump to " + request.DestinationSystem
+	}
+	return fmt.Sprintf("%dt %s to %s", request.Quantity, request.Commodity, request.DestinationSystem)
+}
+
+// Informs the requester about the status change. Failing to send the mail never fails the status change.
+func notifyRequester(request *entities.LogisticsRequest) {
+	requester := request.Requester
+	if requester == nil {
+		requester = &entities.User{}
+		if res := db.DB.Where("id = ?", request.RequesterID).First(requester); res.Error != nil {
+			log.Println("Could not load requester of logistics request", request.ID, res.Error)
+			return
+		}
+	}
+
+	carrierName := ""
+	if request.Carrier != nil {
+		carrierName = request.Carrier.Name
+	}
+
+	err := mailer.SendMailGraceful(requester.Email, mails.LogisticsRequestStatusMail{
+		RequestID:   request.ID,
+		Nickname:    requester.Nickname,
+		Summary:     Describe(request),
+		Status:      string(request.Status),
+		CarrierName: carrierName,
+		Response:    request.Response,
+	}, requester.Locale)
+	if err != nil {
+		log.Println("Could not send logistics request status mail", request.ID, err)
+	}
+}
@@ -0,0 +1,33 @@
+package operations
+
+import "ruehrstaat-backend/errors"
+
+var ErrPackageOperations = errors.NewPackage("Operations", "O")
+
+// codes
+// 1xxx - invalid something
+// 2xxx - not found
+// 3xxx - already done / exists
+// 4xxx - forbidden
+// 5xxx - server error
+
+// 9xxx - other
+// 9999 - unknown error
+
+var (
+	ErrBadRequest         = errors.NewWithInternalMessage(1001, *ErrPackageOperations, 400, "", "Bad Request", "In sentry there might be a more detailed error above")
+	ErrInvalidOperationId = errors.New(1002, *ErrPackageOperations, 400, "", "Invalid Operation ID")
+	ErrInvalidCarrierId   = errors.New(1003, *ErrPackageOperations, 400, "", "Invalid Carrier ID")
+	ErrInvalidTimeWindow  = errors.New(1004, *ErrPackageOperations, 400, "", "Operation has to end after it starts")
+	ErrInvalidCategory    = errors.New(1005, *ErrPackageOperations, 400, "", "Invalid Operation category")
+	ErrInvalidRsvpStatus  = errors.New(1006, *ErrPackageOperations, 400, "", "Invalid RSVP status")
+
+	ErrOperationNotFound = errors.New(2001, *ErrPackageOperations, 404, "", "Operation not found")
+	ErrCarrierNotFound   = errors.New(2002, *ErrPackageOperations, 404, "", "Carrier not found")
+
+	ErrOperationEnded = errors.New(3001, *ErrPackageOperations, 409, "", "Operation has already ended")
+
+	ErrForbidden = errors.New(4000, *ErrPackageOperations, 403, "", "Forbidden")
+
+	ErrInternalServerError = errors.NewWithInternalMessage(5001, *ErrPackageOperations, 500, "", "Internal Server Error", "In sentry there might be a more detailed error above")
+)
@@ -0,0 +1,121 @@
+package operations
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"ruehrstaat-backend/logging"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var log = logging.Logger{Package: "services/operations"}
+
+// Loads the operation including carriers and RSVPs.
+func Find(id uuid.UUID) (*entities.Operation, *errors.RstError) {
+	operation := &entities.Operation{}
+	if res := db.DB.Where("id = ?", id).Preload("Carriers").Preload("Rsvps.User").First(operation); res.Error != nil {
+		if res.Error == gorm.ErrRecordNotFound {
+			return nil, ErrOperationNotFound
+		}
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return operation, nil
+}
+
+// Returns operations that have not ended yet, or past ones, ordered by their start.
+func List(past bool, onlyPublic bool) ([]entities.Operation, *errors.RstError) {
+	query := db.DB.Preload("Carriers").Preload("Rsvps")
+	if past {
+		query = query.Where("ends_at < ?", time.Now()).Order("starts_at DESC")
+	} else {
+		query = query.Where("ends_at >= ?", time.Now()).Order("starts_at")
+	}
+	if onlyPublic {
+		query = query.Where("is_public = ?", true)
+	}
+
+	operations := []entities.Operation{}
+	if res := query.Limit(100).Find(&operations); res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+	return operations, nil
+}
+
+// Validates the fields of a new or changed operation.
+func Validate(operation *entities.Operation) *errors.RstError {
+	if operation.Title == "" {
+		return ErrBadRequest
+	}
+
+	if !operation.EndsAt.After(operation.StartsAt) {
+		return ErrInvalidTimeWindow
+	}
+
+	switch operation.Category {
+	case entities.OperationCategoryMining, entities.OperationCategoryExpedition, entities.OperationCategoryCommunityGoal, entities.OperationCategoryOther:
+		return nil
+	default:
+		return ErrInvalidCategory
+	}
+}
+
+// Whether the user may change the operation, i.e. created it or is an admin.
+func CanManage(user *entities.User, operation *entities.Operation) bool {
+	return user.IsAdmin || (operation.CreatedByID != nil && *operation.CreatedByID == user.ID)
+}
+
+func AssignCarrier(operation *entities.Operation, cr *entities.Carrier) *errors.RstError {
+	if err := db.DB.Model(operation).Association("Carriers").Append(cr); err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+	return nil
+}
+
+func UnassignCarrier(operation *entities.Operation, cr *entities.Carrier) *errors.RstError {
+	if err := db.DB.Model(operation).Association("Carriers").Delete(cr); err != nil {
+		return errors.NewDBErrorFromError(err)
+	}
+	return nil
+}
+
+// Creates or updates the RSVP of the user.
+func Rsvp(operation *entities.Operation, user *entities.User, status entities.OperationRsvpStatus, note string) (*entities.OperationRsvp, *errors.RstError) {
+	switch status {
+	case entities.OperationRsvpGoing, entities.OperationRsvpMaybe, entities.OperationRsvpDeclined:
+	default:
+		return nil, ErrInvalidRsvpStatus
+	}
+
+	if operation.EndsAt.Before(time.Now()) {
+		return nil, ErrOperationEnded
+	}
+
+	rsvp := &entities.OperationRsvp{
+		OperationID: operation.ID,
+		UserID:      user.ID,
+		Status:      status,
+		Note:        note,
+	}
+
+	res := db.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "operation_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "note", "updated_at"}),
+	}).Create(rsvp)
+	if res.Error != nil {
+		return nil, errors.NewDBErrorFromError(res.Error)
+	}
+
+	rsvp.User = user
+	return rsvp, nil
+}
+
+func RemoveRsvp(operation *entities.Operation, user *entities.User) *errors.RstError {
+	if res := db.DB.Unscoped().Where("operation_id = ? AND user_id = ?", operation.ID, user.ID).Delete(&entities.OperationRsvp{}); res.Error != nil {
+		return errors.NewDBErrorFromError(res.Error)
+	}
+	return nil
+}
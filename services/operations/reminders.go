@@ -0,0 +1,66 @@
+package operations
+
+import (
+	"os"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/mailer"
+	"ruehrstaat-backend/mailer/mails"
+	"time"
+)
+
+const defaultReminderOffset = time.Hour
+
+// How long before the start reminders are sent, configured via OPERATION_REMINDER_OFFSET (e.g. "2h").
+func reminderOffset() time.Duration {
+	offset, err := time.ParseDuration(os.Getenv("OPERATION_REMINDER_OFFSET"))
+	if err != nil || offset <= 0 {
+		return defaultReminderOffset
+	}
+	return offset
+}
+
+// Sends reminder emails to everyone who RSVP'd going or maybe to an operation starting soon.
+// Every operation is only reminded of once, unless its start changes.
+func SendReminders() {
+	now := time.Now()
+
+	operations := []entities.Operation{}
+	res := db.DB.Where("reminder_sent_at IS NULL AND starts_at > ? AND starts_at <= ?", now, now.Add(reminderOffset())).
+		Preload("Rsvps", "status IN ?", []entities.OperationRsvpStatus{entities.OperationRsvpGoing, entities.OperationRsvpMaybe}).
+		Preload("Rsvps.User").
+		Find(&operations)
+	if res.Error != nil {
+		log.Println("Could not load operations to remind of", res.Error)
+		return
+	}
+
+	for _, operation := range operations {
+		// claim the operation first so concurrent runs never send twice
+		claim := db.DB.Model(&entities.Operation{}).Where("id = ? AND reminder_sent_at IS NULL", operation.ID).Update("reminder_sent_at", now)
+		if claim.Error != nil {
+			log.Println("Could not mark operation as reminded", operation.ID, claim.Error)
+			continue
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		for _, rsvp := range operation.Rsvps {
+			if rsvp.User == nil {
+				continue
+			}
+
+			err := mailer.SendMailGraceful(rsvp.User.Email, mails.OperationReminderMail{
+				OperationID:   operation.ID,
+				Nickname:      rsvp.User.Nickname,
+				Title:         operation.Title,
+				StartsAt:      operation.StartsAt,
+				TargetSystems: operation.TargetSystems,
+			}, rsvp.User.Locale)
+			if err != nil {
+				log.Println("Could not send operation reminder", operation.ID, rsvp.UserID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package operations
+
+import (
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Jumps shortly before the start are included so the timeline shows carriers getting into position.
+const timelineLeadTime = time.Hour * 24
+
+type TimelineEntryType string
+
+const (
+	TimelineEntryStart TimelineEntryType = "start"
+	TimelineEntryEnd   TimelineEntryType = "end"
+	TimelineEntryJump  TimelineEntryType = "jump"
+)
+
+type TimelineEntry struct {
+	Type    TimelineEntryType
+	At      time.Time
+	Carrier *entities.Carrier
+	Jump    *entities.CarrierJump
+}
+
+// Builds the timeline of the operation from its time window and the scheduled jumps of its carriers.
+// The operation needs its carriers loaded, see Find.
+func Timeline(operation *entities.Operation) ([]TimelineEntry, *errors.RstError) {
+	timeline := []TimelineEntry{
+		{Type: TimelineEntryStart, At: operation.StartsAt},
+		{Type: TimelineEntryEnd, At: operation.EndsAt},
+	}
+
+	if len(operation.Carriers) > 0 {
+		carriers := map[uuid.UUID]*entities.Carrier{}
+		carrierIds := []uuid.UUID{}
+		for i := range operation.Carriers {
+			carriers[operation.Carriers[i].ID] = &operation.Carriers[i]
+			carrierIds = append(carrierIds, operation.Carriers[i].ID)
+		}
+
+		jumps := []entities.CarrierJump{}
+		res := db.DB.Where("carrier_id IN ? AND status IN ? AND departure_at BETWEEN ? AND ?",
+			carrierIds,
+			[]entities.CarrierJumpStatus{entities.CarrierJumpStatusPending, entities.CarrierJumpStatusPlanned, entities.CarrierJumpStatusCompleted},
+			operation.StartsAt.Add(-timelineLeadTime),
+			operation.EndsAt,
+		).Find(&jumps)
+		if res.Error != nil {
+			return nil, errors.NewDBErrorFromError(res.Error)
+		}
+
+		for i := range jumps {
+			timeline = append(timeline, TimelineEntry{
+				Type:    TimelineEntryJump,
+				At:      jumps[i].DepartureAt,
+				Carrier: carriers[jumps[i].CarrierID],
+				Jump:    &jumps[i],
+			})
+		}
+	}
+
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].At.Before(timeline[j].At)
+	})
+	return timeline, nil
+}
@@ -0,0 +1,38 @@
+package outbound
+
+import (
+	"ruehrstaat-backend/db/entities"
+)
+
+// Account of the carrier owner on the third party site.
+type Account struct {
+	CmdrName string
+	ApiKey   string
+}
+
+// Pushes queued carrier state to a third party site, implemented per site.
+type Adapter interface {
+	Push(job *entities.CarrierSyncJob, cr *entities.Carrier, account Account) error
+}
+
+// Failure that retrying will not fix, e.g. a rejected API key.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func permanent(err error) error {
+	return &PermanentError{Err: err}
+}
+
+// Whether a failed HTTP request with the given status is worth retrying.
+func retryableStatus(status int) bool {
+	return status >= 500 || status == 429
+}
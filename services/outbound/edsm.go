@@ -0,0 +1,143 @@
+package outbound
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"ruehrstaat-backend/constants"
+	"ruehrstaat-backend/db/entities"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const defaultEdsmEndpoint = "https://www.edsm.net/api-journal-v1"
+
+// Pushes carrier state as journal events to EDSM's journal upload API.
+type EdsmAdapter struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// Adapter for the url in EDSM_API_URL, e.g. a local fake server.
+func NewEdsmAdapter() *EdsmAdapter {
+	endpoint := os.Getenv("EDSM_API_URL")
+	if endpoint == "" {
+		endpoint = defaultEdsmEndpoint
+	}
+
+	return &EdsmAdapter{
+		Endpoint: endpoint,
+		Client:   &http.Client{Timeout: time.Second * 15},
+	}
+}
+
+type edsmResponse struct {
+	MsgNum int    `json:"msgnum"`
+	Msg    string `json:"msg"`
+	Events []struct {
+		MsgNum int    `json:"msgnum"`
+		Msg    string `json:"msg"`
+	} `json:"events"`
+}
+
+// Builds the journal event the game would have written for the job.
+func edsmJournalEvent(job *entities.CarrierSyncJob, cr *entities.Carrier, carrierID int64) map[string]interface{} {
+	timestamp := job.OccurredAt.UTC().Format(time.RFC3339)
+
+	if job.Event == entities.CarrierSyncEventJump {
+		return map[string]interface{}{
+			"timestamp":  timestamp,
+			"event":      "CarrierLocation",
+			"CarrierID":  carrierID,
+			"StarSystem": job.StarSystem,
+		}
+	}
+
+	return map[string]interface{}{
+		"timestamp":      timestamp,
+		"event":          "CarrierStats",
+		"CarrierID":      carrierID,
+		"Callsign":       cr.Callsign,
+		"Name":           cr.Name,
+		"DockingAccess":  cr.DockingAccess,
+		"AllowNotorious": cr.AllowNotorious,
+		"FuelLevel":      cr.FuelLevel,
+		"SpaceUsage": map[string]interface{}{
+			"TotalCapacity": cr.CargoSpace,
+			"Cargo":         cr.CargoUsed,
+			"FreeSpace":     cr.CargoSpace - cr.CargoUsed,
+		},
+		"Finance": map[string]interface{}{
+			"CarrierBalance":   cr.Balance,
+			"ReserveBalance":   cr.ReserveBalance,
+			"AvailableBalance": cr.AvailableBalance,
+		},
+	}
+}
+
+func (a *EdsmAdapter) Push(job *entities.CarrierSyncJob, cr *entities.Carrier, account Account) error {
+	carrierID, err := strconv.ParseInt(cr.MarketID, 10, 64)
+	if err != nil {
+		return permanent(fmt.Errorf("Invalid market id %s", cr.MarketID))
+	}
+
+	message, err := jsoniter.MarshalToString(edsmJournalEvent(job, cr, carrierID))
+	if err != nil {
+		return permanent(err)
+	}
+
+	form := url.Values{}
+	form.Set("commanderName", account.CmdrName)
+	form.Set("apiKey", account.ApiKey)
+	form.Set("fromSoftware", constants.APP_NAME)
+	form.Set("fromSoftwareVersion", constants.APP_VERSION)
+	form.Set("message", message)
+
+	res, err := a.Client.Post(a.Endpoint, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err := fmt.Errorf("EDSM responded with %d", res.StatusCode)
+		if retryableStatus(res.StatusCode) {
+			return err
+		}
+		return permanent(err)
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := edsmResponse{}
+	if err := jsoniter.Unmarshal(raw, &response); err != nil {
+		return err
+	}
+
+	// 1xx: stored, 3xx: discarded by EDSM, 5xx: server error, everything else was rejected (e.g. unknown commander or API key)
+	codes := []int{response.MsgNum}
+	messages := []string{response.Msg}
+	for _, event := range response.Events {
+		codes = append(codes, event.MsgNum)
+		messages = append(messages, event.Msg)
+	}
+
+	for i, code := range codes {
+		if code >= 500 {
+			return fmt.Errorf("EDSM failed with %d: %s", code, messages[i])
+		}
+		if code >= 200 && (code < 300 || code >= 400) {
+			return permanent(fmt.Errorf("EDSM rejected the event with %d: %s", code, messages[i]))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package outbound
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"ruehrstaat-backend/constants"
+	"ruehrstaat-backend/db/entities"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Answers every request with the status and body, the decoded journal event is passed to inspect.
+func edsmServer(t *testing.T, status int, body string, inspect func(r *http.Request, event map[string]interface{})) *EdsmAdapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("unexpected request %s with %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("invalid form: %v", err)
+		}
+
+		event := map[string]interface{}{}
+		if err := jsoniter.UnmarshalFromString(r.PostForm.Get("message"), &event); err != nil {
+			t.Errorf("invalid message: %v", err)
+		}
+		if inspect != nil {
+			inspect(r, event)
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &EdsmAdapter{Endpoint: server.URL, Client: server.Client()}
+}
+
+func TestEdsmRequestShape(t *testing.T) {
+	adapter := edsmServer(t, http.StatusOK, `{"msgnum":100,"msg":"OK","events":[{"msgnum":100,"msg":"Message queued"}]}`, func(r *http.Request, event map[string]interface{}) {
+		if r.PostForm.Get("commanderName") != "Tester" || r.PostForm.Get("apiKey") != "secret" {
+			t.Errorf("unexpected account %v", r.PostForm)
+		}
+		if r.PostForm.Get("fromSoftware") != constants.APP_NAME || r.PostForm.Get("fromSoftwareVersion") != constants.APP_VERSION {
+			t.Errorf("unexpected software %v", r.PostForm)
+		}
+		if event["event"] != "CarrierLocation" || event["CarrierID"] != float64(3700005632) || event["StarSystem"] != "Deciat" || event["timestamp"] != "2025-03-14T18:02:09Z" {
+			t.Errorf("unexpected event %v", event)
+		}
+	})
+
+	if err := adapter.Push(testJob(entities.CarrierSyncEventJump), testCarrier(), testAccount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEdsmStatsEvent(t *testing.T) {
+	adapter := edsmServer(t, http.StatusOK, `{"msgnum":100,"msg":"OK"}`, func(r *http.Request, event map[string]interface{}) {
+		if event["event"] != "CarrierStats" || event["Callsign"] != "K7Q-BQL" || event["FuelLevel"] != float64(785) {
+			t.Errorf("unexpected event %v", event)
+		}
+		space, _ := event["SpaceUsage"].(map[string]interface{})
+		if space["FreeSpace"] != float64(23500) {
+			t.Errorf("unexpected space usage %v", event["SpaceUsage"])
+		}
+	})
+
+	if err := adapter.Push(testJob(entities.CarrierSyncEventStats), testCarrier(), testAccount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEdsmErrorMapping(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    int
+		body      string
+		fails     bool
+		permanent bool
+	}{
+		{"stored", http.StatusOK, `{"msgnum":100,"msg":"OK","events":[{"msgnum":100,"msg":"Message queued"}]}`, false, false},
+		{"discarded", http.StatusOK, `{"msgnum":100,"msg":"OK","events":[{"msgnum":304,"msg":"Discarded event"}]}`, false, false},
+		{"unknown commander", http.StatusOK, `{"msgnum":203,"msg":"Commander name/API Key not found"}`, true, true},
+		{"event rejected", http.StatusOK, `{"msgnum":100,"msg":"OK","events":[{"msgnum":401,"msg":"Category unknown"}]}`, true, true},
+		{"event failed", http.StatusOK, `{"msgnum":100,"msg":"OK","events":[{"msgnum":500,"msg":"Exception"}]}`, true, false},
+		{"server error", http.StatusBadGateway, ``, true, false},
+		{"forbidden", http.StatusForbidden, ``, true, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := edsmServer(t, tc.status, tc.body, nil).Push(testJob(entities.CarrierSyncEventJump), testCarrier(), testAccount)
+			assertPushError(t, err, tc.fails, tc.permanent)
+		})
+	}
+}
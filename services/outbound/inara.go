@@ -0,0 +1,147 @@
+package outbound
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"ruehrstaat-backend/constants"
+	"ruehrstaat-backend/db/entities"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const defaultInaraEndpoint = "https://inara.cz/inapi/v1/"
+
+type InaraAdapter struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// Adapter for the url in INARA_API_URL, e.g. a local fake server.
+func NewInaraAdapter() *InaraAdapter {
+	endpoint := os.Getenv("INARA_API_URL")
+	if endpoint == "" {
+		endpoint = defaultInaraEndpoint
+	}
+
+	return &InaraAdapter{
+		Endpoint: endpoint,
+		Client:   &http.Client{Timeout: time.Second * 15},
+	}
+}
+
+type inaraHeader struct {
+	AppName          string `json:"appName"`
+	AppVersion       string `json:"appVersion"`
+	IsBeingDeveloped bool   `json:"isBeingDeveloped"`
+	APIKey           string `json:"APIkey"`
+	CommanderName    string `json:"commanderName"`
+}
+
+type inaraEvent struct {
+	EventName      string                 `json:"eventName"`
+	EventTimestamp string                 `json:"eventTimestamp"`
+	EventData      map[string]interface{} `json:"eventData"`
+}
+
+type inaraRequest struct {
+	Header inaraHeader  `json:"header"`
+	Events []inaraEvent `json:"events"`
+}
+
+type inaraStatus struct {
+	EventStatus     int    `json:"eventStatus"`
+	EventStatusText string `json:"eventStatusText"`
+}
+
+type inaraResponse struct {
+	Header inaraStatus   `json:"header"`
+	Events []inaraStatus `json:"events"`
+}
+
+func (a *InaraAdapter) Push(job *entities.CarrierSyncJob, cr *entities.Carrier, account Account) error {
+	marketID, err := strconv.ParseInt(cr.MarketID, 10, 64)
+	if err != nil {
+		return permanent(fmt.Errorf("Invalid market id %s", cr.MarketID))
+	}
+
+	data := map[string]interface{}{
+		"carrierName": cr.Name,
+		"callsign":    cr.Callsign,
+		"marketID":    marketID,
+	}
+
+	switch job.Event {
+	case entities.CarrierSyncEventJump:
+		data["starsystemName"] = job.StarSystem
+	case entities.CarrierSyncEventStats:
+		data["starsystemName"] = cr.CurrentLocation
+		data["fuelLevel"] = cr.FuelLevel
+		data["dockingAccess"] = cr.DockingAccess
+		data["allowNotorious"] = cr.AllowNotorious
+		data["carrierBalance"] = cr.Balance
+		data["reserveBalance"] = cr.ReserveBalance
+		data["availableBalance"] = cr.AvailableBalance
+		data["cargoSpace"] = cr.CargoSpace
+		data["cargoUsed"] = cr.CargoUsed
+	}
+
+	body, err := jsoniter.Marshal(inaraRequest{
+		Header: inaraHeader{
+			AppName:          constants.APP_NAME,
+			AppVersion:       constants.APP_VERSION,
+			IsBeingDeveloped: os.Getenv("GIN_MODE") != "release",
+			APIKey:           account.ApiKey,
+			CommanderName:    account.CmdrName,
+		},
+		Events: []inaraEvent{{
+			EventName:      "setCommanderFleetCarrier",
+			EventTimestamp: job.OccurredAt.UTC().Format(time.RFC3339),
+			EventData:      data,
+		}},
+	})
+	if err != nil {
+		return permanent(err)
+	}
+
+	res, err := a.Client.Post(a.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err := fmt.Errorf("Inara responded with %d", res.StatusCode)
+		if retryableStatus(res.StatusCode) {
+			return err
+		}
+		return permanent(err)
+	}
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	response := inaraResponse{}
+	if err := jsoniter.Unmarshal(raw, &response); err != nil {
+		return err
+	}
+
+	// the header reports problems with the whole request (e.g. an invalid API key), events their own problems
+	statuses := append([]inaraStatus{response.Header}, response.Events...)
+	for _, status := range statuses {
+		if status.EventStatus >= 500 {
+			return fmt.Errorf("Inara failed with %d: %s", status.EventStatus, status.EventStatusText)
+		}
+		if status.EventStatus >= 400 {
+			return permanent(fmt.Errorf("Inara rejected the event with %d: %s", status.EventStatus, status.EventStatusText))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package outbound
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"ruehrstaat-backend/constants"
+	"ruehrstaat-backend/db/entities"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+func testCarrier() *entities.Carrier {
+	return &entities.Carrier{
+		Name:             "Rührstaat Supply",
+		Callsign:         "K7Q-BQL",
+		MarketID:         "3700005632",
+		CurrentLocation:  "Colonia",
+		DockingAccess:    entities.DockingAccessSquadron,
+		FuelLevel:        785,
+		CargoSpace:       25000,
+		CargoUsed:        1500,
+		Balance:          3210000000,
+		ReserveBalance:   500000000,
+		AvailableBalance: 2710000000,
+	}
+}
+
+func testJob(event entities.CarrierSyncEvent) *entities.CarrierSyncJob {
+	return &entities.CarrierSyncJob{
+		Event:      event,
+		StarSystem: "Deciat",
+		OccurredAt: time.Date(2025, 3, 14, 18, 2, 9, 0, time.UTC),
+	}
+}
+
+var testAccount = Account{CmdrName: "Tester", ApiKey: "secret"}
+
+// Answers every request with the status and body, the decoded request is passed to inspect.
+func inaraServer(t *testing.T, status int, body string, inspect func(request *inaraRequest)) *InaraAdapter {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s with %q", r.Method, r.Header.Get("Content-Type"))
+		}
+
+		request := &inaraRequest{}
+		if err := jsoniter.NewDecoder(r.Body).Decode(request); err != nil {
+			t.Errorf("invalid request body: %v", err)
+		}
+		if inspect != nil {
+			inspect(request)
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return &InaraAdapter{Endpoint: server.URL, Client: server.Client()}
+}
+
+func TestInaraRequestShape(t *testing.T) {
+	adapter := inaraServer(t, http.StatusOK, `{"header":{"eventStatus":200},"events":[{"eventStatus":200}]}`, func(request *inaraRequest) {
+		if request.Header.AppName != constants.APP_NAME || request.Header.AppVersion != constants.APP_VERSION {
+			t.Errorf("unexpected app %q %q", request.Header.AppName, request.Header.AppVersion)
+		}
+		if request.Header.APIKey != "secret" || request.Header.CommanderName != "Tester" {
+			t.Errorf("unexpected account %+v", request.Header)
+		}
+		if len(request.Events) != 1 {
+			t.Fatalf("expected a single event, got %d", len(request.Events))
+		}
+
+		event := request.Events[0]
+		if event.EventName != "setCommanderFleetCarrier" || event.EventTimestamp != "2025-03-14T18:02:09Z" {
+			t.Errorf("unexpected event %q at %q", event.EventName, event.EventTimestamp)
+		}
+		if event.EventData["callsign"] != "K7Q-BQL" || event.EventData["marketID"] != float64(3700005632) || event.EventData["starsystemName"] != "Deciat" {
+			t.Errorf("unexpected event data %v", event.EventData)
+		}
+	})
+
+	if err := adapter.Push(testJob(entities.CarrierSyncEventJump), testCarrier(), testAccount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInaraStatsEvent(t *testing.T) {
+	adapter := inaraServer(t, http.StatusOK, `{"header":{"eventStatus":200},"events":[{"eventStatus":200}]}`, func(request *inaraRequest) {
+		data := request.Events[0].EventData
+		if data["starsystemName"] != "Colonia" || data["fuelLevel"] != float64(785) || data["cargoUsed"] != float64(1500) || data["availableBalance"] != float64(2710000000) {
+			t.Errorf("unexpected stats %v", data)
+		}
+	})
+
+	if err := adapter.Push(testJob(entities.CarrierSyncEventStats), testCarrier(), testAccount); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInaraErrorMapping(t *testing.T) {
+	cases := []struct {
+		name      string
+		status    int
+		body      string
+		fails     bool
+		permanent bool
+	}{
+		{"accepted", http.StatusOK, `{"header":{"eventStatus":200},"events":[{"eventStatus":204}]}`, false, false},
+		{"invalid api key", http.StatusOK, `{"header":{"eventStatus":400,"eventStatusText":"Invalid API key"}}`, true, true},
+		{"event rejected", http.StatusOK, `{"header":{"eventStatus":200},"events":[{"eventStatus":400,"eventStatusText":"Unknown carrier"}]}`, true, true},
+		{"event failed", http.StatusOK, `{"header":{"eventStatus":200},"events":[{"eventStatus":500,"eventStatusText":"Database error"}]}`, true, false},
+		{"server error", http.StatusInternalServerError, ``, true, false},
+		{"rate limited", http.StatusTooManyRequests, ``, true, false},
+		{"bad request", http.StatusBadRequest, ``, true, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := inaraServer(t, tc.status, tc.body, nil).Push(testJob(entities.CarrierSyncEventJump), testCarrier(), testAccount)
+			assertPushError(t, err, tc.fails, tc.permanent)
+		})
+	}
+}
+
+func TestInaraInvalidMarketID(t *testing.T) {
+	cr := testCarrier()
+	cr.MarketID = "unknown"
+
+	err := (&InaraAdapter{Endpoint: "http://127.0.0.1:0", Client: http.DefaultClient}).Push(testJob(entities.CarrierSyncEventJump), cr, testAccount)
+	assertPushError(t, err, true, true)
+}
+
+func assertPushError(t *testing.T, err error, fails bool, isPermanent bool) {
+	t.Helper()
+
+	if !fails {
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	permanentErr := &PermanentError{}
+	if errors.As(err, &permanentErr) != isPermanent {
+		t.Fatalf("expected permanent = %v, got %v", isPermanent, err)
+	}
+}
@@ -0,0 +1,218 @@
+package outbound
+
+import (
+	"errors"
+	"fmt"
+	"ruehrstaat-backend/db"
+	"ruehrstaat-backend/db/entities"
+	"ruehrstaat-backend/logging"
+	"ruehrstaat-backend/util"
+	"sync"
+	"time"
+)
+
+var log = logging.Logger{Package: "services/outbound"}
+
+const (
+	maxAttempts    = 8
+	retryBaseDelay = time.Minute
+	maxRetryDelay  = time.Hour * 6
+	batchSize      = 100
+)
+
+// Processes queued jobs with the adapter for their target.
+type Queue struct {
+	Adapters map[entities.CarrierSyncTarget]Adapter
+}
+
+var (
+	defaultQueue     *Queue
+	defaultQueueOnce sync.Once
+)
+
+// Shared queue with the adapters configured from the environment.
+func DefaultQueue() *Queue {
+	defaultQueueOnce.Do(func() {
+		defaultQueue = &Queue{
+			Adapters: map[entities.CarrierSyncTarget]Adapter{
+				entities.CarrierSyncTargetInara: NewInaraAdapter(),
+				entities.CarrierSyncTargetEdsm:  NewEdsmAdapter(),
+			},
+		}
+	})
+	return defaultQueue
+}
+
+// Targets the carrier opted in to.
+func enabledTargets(cr *entities.Carrier) []entities.CarrierSyncTarget {
+	targets := []entities.CarrierSyncTarget{}
+	if cr.SyncToInara {
+		targets = append(targets, entities.CarrierSyncTargetInara)
+	}
+	if cr.SyncToEdsm {
+		targets = append(targets, entities.CarrierSyncTargetEdsm)
+	}
+	return targets
+}
+
+func isEnabled(cr *entities.Carrier, target entities.CarrierSyncTarget) bool {
+	for _, t := range enabledTargets(cr) {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Queues the changes between both states of the carrier for all targets it opted in to.
+// Stats are pushed as they are when the job runs, so only one stats job is kept per target.
+func CarrierChanged(before *entities.Carrier, after *entities.Carrier) {
+	now := time.Now()
+
+	for _, target := range enabledTargets(after) {
+		if before.CurrentLocation != after.CurrentLocation && after.CurrentLocation != "" {
+			enqueue(&entities.CarrierSyncJob{
+				CarrierID:  after.ID,
+				Target:     target,
+				Event:      entities.CarrierSyncEventJump,
+				StarSystem: after.CurrentLocation,
+				OccurredAt: now,
+			})
+		}
+
+		var pending int64
+		res := db.DB.Model(&entities.CarrierSyncJob{}).Where("carrier_id = ? AND target = ? AND event = ? AND status = ?", after.ID, target, entities.CarrierSyncEventStats, entities.CarrierSyncStatusPending).Count(&pending)
+		if res.Error != nil {
+			log.Println("Could not check queued stats of carrier", after.ID, res.Error)
+			continue
+		}
+		if pending == 0 {
+			enqueue(&entities.CarrierSyncJob{
+				CarrierID:  after.ID,
+				Target:     target,
+				Event:      entities.CarrierSyncEventStats,
+				OccurredAt: now,
+			})
+		}
+	}
+}
+
+func enqueue(job *entities.CarrierSyncJob) {
+	job.Status = entities.CarrierSyncStatusPending
+	job.NextAttemptAt = job.OccurredAt
+
+	if res := db.DB.Create(job); res.Error != nil {
+		log.Println("Could not queue", job.Event, "of carrier", job.CarrierID, "for", job.Target, res.Error)
+	}
+}
+
+// Processes all due jobs of the shared queue, run by the cron system.
+func ProcessQueue() {
+	DefaultQueue().Process()
+}
+
+// Pushes due jobs in the order they occurred.
+func (q *Queue) Process() {
+	jobs := []entities.CarrierSyncJob{}
+	res := db.DB.Where("status = ? AND next_attempt_at <= ?", entities.CarrierSyncStatusPending, time.Now()).
+		Order("occurred_at").
+		Limit(batchSize).
+		Preload("Carrier.Owner").
+		Find(&jobs)
+	if res.Error != nil {
+		log.Println("Could not load queued carrier sync jobs", res.Error)
+		return
+	}
+
+	for i := range jobs {
+		q.process(&jobs[i])
+	}
+}
+
+func (q *Queue) process(job *entities.CarrierSyncJob) {
+	// the carrier was deleted or opted out after the job was queued
+	if job.Carrier == nil || !isEnabled(job.Carrier, job.Target) {
+		q.finish(job)
+		return
+	}
+
+	err := q.push(job)
+	if err == nil {
+		q.finish(job)
+		return
+	}
+
+	message := err.Error()
+	job.LastError = &message
+	job.Attempts++
+
+	permanentErr := &PermanentError{}
+	if errors.As(err, &permanentErr) || job.Attempts >= maxAttempts {
+		job.Status = entities.CarrierSyncStatusFailed
+		log.Println("Giving up pushing", job.Event, "of carrier", job.CarrierID, "to", job.Target, err)
+	} else {
+		job.NextAttemptAt = time.Now().Add(retryDelay(job.Attempts))
+	}
+
+	if res := db.DB.Save(job); res.Error != nil {
+		log.Println("Could not store carrier sync job", job.ID, res.Error)
+	}
+}
+
+func (q *Queue) push(job *entities.CarrierSyncJob) error {
+	adapter, ok := q.Adapters[job.Target]
+	if !ok {
+		return permanent(fmt.Errorf("No adapter for %s", job.Target))
+	}
+
+	account, err := ownerAccount(job.Carrier, job.Target)
+	if err != nil {
+		return permanent(err)
+	}
+
+	return adapter.Push(job, job.Carrier, *account)
+}
+
+// Removes a job that needs no further attempts.
+func (q *Queue) finish(job *entities.CarrierSyncJob) {
+	if res := db.DB.Unscoped().Delete(job); res.Error != nil {
+		log.Println("Could not remove carrier sync job", job.ID, res.Error)
+	}
+}
+
+// Doubles the delay with every attempt, starting at retryBaseDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= maxRetryDelay {
+			return maxRetryDelay
+		}
+	}
+	return delay
+}
+
+// Account of the carrier owner with the decrypted API key for the target.
+func ownerAccount(cr *entities.Carrier, target entities.CarrierSyncTarget) (*Account, error) {
+	if cr.Owner == nil {
+		return nil, errors.New("Carrier has no owner to sync as")
+	}
+
+	var encrypted *string
+	switch target {
+	case entities.CarrierSyncTargetInara:
+		encrypted = cr.Owner.InaraApiKey
+	case entities.CarrierSyncTargetEdsm:
+		encrypted = cr.Owner.EdsmApiKey
+	}
+	if encrypted == nil {
+		return nil, fmt.Errorf("Owner has no %s API key", target)
+	}
+
+	key, err := util.Decrypt(*encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Account{CmdrName: cr.Owner.CmdrName, ApiKey: key}, nil
+}
@@ -0,0 +1,47 @@
+package outbound
+
+import (
+	"ruehrstaat-backend/db/entities"
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	cases := map[int]time.Duration{
+		1: time.Minute,
+		2: time.Minute * 2,
+		3: time.Minute * 4,
+		8: time.Minute * 128,
+		// capped at maxRetryDelay
+		10: time.Hour * 6,
+		50: time.Hour * 6,
+	}
+
+	for attempts, expected := range cases {
+		if delay := retryDelay(attempts); delay != expected {
+			t.Errorf("attempt %d: expected %v, got %v", attempts, expected, delay)
+		}
+	}
+}
+
+func TestPushWithoutAdapterFailsPermanently(t *testing.T) {
+	queue := &Queue{Adapters: map[entities.CarrierSyncTarget]Adapter{}}
+	job := testJob(entities.CarrierSyncEventJump)
+	job.Target = entities.CarrierSyncTargetInara
+	job.Carrier = testCarrier()
+
+	assertPushError(t, queue.push(job), true, true)
+}
+
+func TestPushWithoutApiKeyFailsPermanently(t *testing.T) {
+	queue := &Queue{Adapters: map[entities.CarrierSyncTarget]Adapter{entities.CarrierSyncTargetEdsm: &EdsmAdapter{}}}
+	job := testJob(entities.CarrierSyncEventJump)
+	job.Target = entities.CarrierSyncTargetEdsm
+	job.Carrier = testCarrier()
+
+	// without an owner there is nobody to sync as
+	assertPushError(t, queue.push(job), true, true)
+
+	job.Carrier.Owner = &entities.User{CmdrName: "Tester"}
+	assertPushError(t, queue.push(job), true, true)
+}
@@ -0,0 +1,134 @@
+package presence
+
+import (
+	"context"
+	"os"
+	"ruehrstaat-backend/cache"
+	"ruehrstaat-backend/logging"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var log = logging.Logger{Package: "services/presence"}
+
+// Presence without any new event for this long is considered stale, configurable via PRESENCE_TTL (e.g. "6h").
+const defaultPresenceTTL = time.Hour * 6
+
+// Key holding the carrier a user is aboard of
+func userKey(userID uuid.UUID) string {
+	return "presence:user:" + userID.String()
+}
+
+// Sorted set of users aboard a carrier, scored by the unix time their presence expires
+func carrierKey(carrierID string) string {
+	return "presence:carrier:" + carrierID
+}
+
+func presenceTTL() time.Duration {
+	ttl, err := time.ParseDuration(os.Getenv("PRESENCE_TTL"))
+	if err != nil || ttl <= 0 {
+		return defaultPresenceTTL
+	}
+	return ttl
+}
+
+// Marks the user as aboard the carrier, leaving any carrier the user was aboard before.
+// Also used to refresh the presence, e.g. on a CarrierJump while docked.
+func Board(userID uuid.UUID, carrierID uuid.UUID) error {
+	ctx := context.Background()
+	ttl := presenceTTL()
+
+	previous, err := cache.Redis.Get(ctx, userKey(userID)).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+
+	_, err = cache.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" && previous != carrierID.String() {
+			pipe.ZRem(ctx, carrierKey(previous), userID.String())
+		}
+		pipe.Set(ctx, userKey(userID), carrierID.String(), ttl)
+		pipe.ZAdd(ctx, carrierKey(carrierID.String()), redis.Z{Score: float64(time.Now().Add(ttl).Unix()), Member: userID.String()})
+		pipe.Expire(ctx, carrierKey(carrierID.String()), ttl)
+		return nil
+	})
+	return err
+}
+
+// Marks the user as not aboard any carrier.
+func Leave(userID uuid.UUID) error {
+	ctx := context.Background()
+
+	previous, err := cache.Redis.Get(ctx, userKey(userID)).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = cache.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, carrierKey(previous), userID.String())
+		pipe.Del(ctx, userKey(userID))
+		return nil
+	})
+	return err
+}
+
+// Returns the ids of the users currently aboard the carrier.
+func Aboard(carrierID uuid.UUID) ([]uuid.UUID, error) {
+	ctx := context.Background()
+	if err := removeStale(ctx, carrierID); err != nil {
+		return nil, err
+	}
+
+	members, err := cache.Redis.ZRange(ctx, carrierKey(carrierID.String()), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []uuid.UUID{}
+	for _, member := range members {
+		if id, err := uuid.Parse(member); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// Returns the number of users currently aboard each of the carriers in one round trip, carriers are missing if their count can not be determined.
+func Counts(carrierIDs []uuid.UUID) map[uuid.UUID]int {
+	counts := map[uuid.UUID]int{}
+	if cache.Redis == nil || len(carrierIDs) == 0 {
+		return counts
+	}
+
+	ctx := context.Background()
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	cards := make([]*redis.IntCmd, len(carrierIDs))
+	_, err := cache.Redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, carrierID := range carrierIDs {
+			pipe.ZRemRangeByScore(ctx, carrierKey(carrierID.String()), "-inf", now)
+			cards[i] = pipe.ZCard(ctx, carrierKey(carrierID.String()))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Println("Could not count presence", err)
+		return counts
+	}
+
+	for i, carrierID := range carrierIDs {
+		counts[carrierID] = int(cards[i].Val())
+	}
+	return counts
+}
+
+// Members of the carrier set expire individually, so expired ones are removed before reading.
+func removeStale(ctx context.Context, carrierID uuid.UUID) error {
+	return cache.Redis.ZRemRangeByScore(ctx, carrierKey(carrierID.String()), "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Err()
+}
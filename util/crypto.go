@@ -16,6 +16,12 @@ func GenerateReadableRandomString(n int) (string, error) {
 	return generateRandomStringFromCharset(n, letters)
 }
 
+// Consonants only so codes typed in by users are unambiguous and can not spell words.
+func GenerateUserCode(n int) (string, error) {
+	const letters = "BCDFGHJKLMNPQRSTVWXZ"
+	return generateRandomStringFromCharset(n, letters)
+}
+
 // Generate randoim number string
 func GenerateRandomNumberString(n int) (string, error) {
 	const letters = "0123456789"